@@ -58,7 +58,8 @@ func NewStore(init StoreInit) (s *Store, err error) {
 			return nil, fmt.Errorf("Error converting value for key '%v' to UTF-8: %v", key, err)
 		}
 
-		if !localStore.hasKey(key) {
+		// only check local entries, as values from parent stores may be overridden
+		if _, exists := localStore.store[key]; !exists {
 			localStore.Set(key, value)
 		} else {
 			return nil, fmt.Errorf("Duplicate key '%v' found", key)
@@ -214,10 +215,19 @@ func (s *Store) GetKeys() (keyList []string) {
 // GetArgStoresFromCsvRecords gets a list of records from a CSV file and returns a list
 //  of ArgStores that contain the required arguments to fill out a chronicle.
 func GetArgStoresFromCsvRecords(records [][]string) (argStores []*Store, err error) {
+	argStores, _, err = GetArgStoresAndColumnsFromCsvRecords(records)
+	return argStores, err
+}
+
+// GetArgStoresAndColumnsFromCsvRecords works like GetArgStoresFromCsvRecords, but additionally
+// returns for each ArgStore the number of the CSV column from which it was read. Column numbers
+// start with 1, so the values for the first player are found in column 2.
+func GetArgStoresAndColumnsFromCsvRecords(records [][]string) (argStores []*Store, columns []int, err error) {
 	argStores = make([]*Store, 0)
+	columns = make([]int, 0)
 
 	if len(records) == 0 {
-		return argStores, nil
+		return argStores, columns, nil
 	}
 
 	numPlayers := len(records[0]) - 1
@@ -237,16 +247,16 @@ func GetArgStoresFromCsvRecords(records [][]string) (argStores []*Store, err err
 
 			// handle duplicate keys
 			if store.hasKey(key) {
-				return nil, fmt.Errorf("Input data contains multiple lines for content ID '%v'", key)
+				return nil, nil, fmt.Errorf("Input data contains multiple lines for content ID '%v'", key)
 			}
 
 			// only add to store if there is an actual value
 			if csvRecordHasValue(value) {
 				if !utils.IsSet(key) {
-					return nil, fmt.Errorf("CSV Line has content value '%v', but is missing content ID in first column", value)
+					return nil, nil, fmt.Errorf("CSV Line has content value '%v', but is missing content ID in first column", value)
 				}
 				if value, err = encode.ConvertStringToUtf8(value); err != nil {
-					return nil, fmt.Errorf("Error converting value for key '%v' to UTF-8: %v", key, err)
+					return nil, nil, fmt.Errorf("Error converting value for key '%v' to UTF-8: %v", key, err)
 				}
 				store.Set(key, value)
 			}
@@ -255,10 +265,11 @@ func GetArgStoresFromCsvRecords(records [][]string) (argStores []*Store, err err
 		// only add store if it is not empty
 		if store.numEntries() >= 1 {
 			argStores = append(argStores, store)
+			columns = append(columns, idx+1)
 		}
 	}
 
-	return argStores, nil
+	return argStores, columns, nil
 }
 
 // csvRecordHasValue checks if a record read from a CSV file is not empty and does not begin
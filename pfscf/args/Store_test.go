@@ -150,3 +150,52 @@ func TestGetArgStoresFromCsvFile(t *testing.T) {
 		})
 	})
 }
+
+func TestGetArgStoresAndColumnsFromCsvRecords(t *testing.T) {
+	t.Run("errors", func(t *testing.T) {
+		records := getCsvRecords(t, filepath.Join(argStoreTestDir, "duplicateContent.csv"))
+		as, columns, err := GetArgStoresAndColumnsFromCsvRecords(records)
+		test.ExpectNil(t, as)
+		test.ExpectNil(t, columns)
+		test.ExpectError(t, err)
+	})
+
+	t.Run("valid", func(t *testing.T) {
+		records := getCsvRecords(t, filepath.Join(argStoreTestDir, "emptyPlayerColumn.csv"))
+		argStores, columns, err := GetArgStoresAndColumnsFromCsvRecords(records)
+		test.ExpectNoError(t, err)
+
+		test.ExpectEqual(t, len(argStores), 2)
+		test.ExpectEqual(t, len(columns), 2)
+		test.ExpectEqual(t, columns[0], 2)
+		test.ExpectEqual(t, columns[1], 4)
+
+		player, _ := argStores[1].Get("player")
+		test.ExpectEqual(t, player, "Hanna")
+	})
+}
+
+func TestNewStore(t *testing.T) {
+	t.Run("errors", func(t *testing.T) {
+		t.Run("duplicate key", func(t *testing.T) {
+			as, err := NewStore(StoreInit{Args: []string{"foo=bar", "foo=baz"}})
+			test.ExpectError(t, err, "Duplicate key 'foo'")
+			test.ExpectNil(t, as)
+		})
+	})
+
+	t.Run("valid", func(t *testing.T) {
+		t.Run("override values from parent", func(t *testing.T) {
+			parent, err := NewStore(StoreInit{Args: []string{"foo=parent", "bar=parent"}})
+			test.ExpectNoError(t, err)
+
+			as, err := NewStore(StoreInit{Args: []string{"foo=child"}, Parent: parent})
+			test.ExpectNoError(t, err)
+
+			value, _ := as.Get("foo")
+			test.ExpectEqual(t, value, "child")
+			value, _ = as.Get("bar")
+			test.ExpectEqual(t, value, "parent")
+		})
+	})
+}
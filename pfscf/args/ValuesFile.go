@@ -0,0 +1,38 @@
+package args
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/Blesmol/pfscf/pfscf/yaml"
+)
+
+// ReadValuesFile reads a file containing a mapping from parameter IDs to values. Files
+// with extension ".json" are read as JSON, all other files are read as YAML. Values can
+// either be single values or lists of values.
+func ReadValuesFile(filename string) (values map[string]interface{}, err error) {
+	values = make(map[string]interface{})
+
+	if strings.ToLower(filepath.Ext(filename)) == ".json" {
+		file, err := os.Open(filename)
+		if err != nil {
+			return nil, fmt.Errorf("Error reading file '%v': %v", filename, err)
+		}
+		defer file.Close()
+
+		decoder := json.NewDecoder(file)
+		decoder.UseNumber() // keep numbers as they are written in the file
+		if err = decoder.Decode(&values); err != nil {
+			return nil, fmt.Errorf("Error parsing file '%v': %v", filename, err)
+		}
+		return values, nil
+	}
+
+	if err = yaml.ReadYamlFile(filename, &values); err != nil {
+		return nil, err
+	}
+	return values, nil
+}
@@ -0,0 +1,41 @@
+package args
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"testing"
+
+	test "github.com/Blesmol/pfscf/pfscf/testutils"
+)
+
+func TestReadValuesFile(t *testing.T) {
+	t.Run("errors", func(t *testing.T) {
+		for _, filename := range []string{"doesNotExist.yml", "doesNotExist.json", "valuesMalformed.yml", "valuesMalformed.json"} {
+			t.Logf("Testing file '%v'", filename)
+			values, err := ReadValuesFile(filepath.Join(argStoreTestDir, filename))
+			test.ExpectError(t, err, filename)
+			test.ExpectNil(t, values)
+		}
+	})
+
+	t.Run("valid", func(t *testing.T) {
+		t.Run("yaml", func(t *testing.T) {
+			values, err := ReadValuesFile(filepath.Join(argStoreTestDir, "values.yml"))
+			test.ExpectNoError(t, err)
+			test.ExpectEqual(t, len(values), 6)
+			test.ExpectEqual(t, values["player"], "Bob")
+			test.ExpectEqual(t, values["gmid"], 12345)
+			test.ExpectEqual(t, values["notes"], "First line\nSecond line\n")
+			test.ExpectEqual(t, len(values["list_items_sold"].([]interface{})), 3)
+		})
+
+		t.Run("json", func(t *testing.T) {
+			values, err := ReadValuesFile(filepath.Join(argStoreTestDir, "values.json"))
+			test.ExpectNoError(t, err)
+			test.ExpectEqual(t, len(values), 4)
+			test.ExpectEqual(t, values["player"], "Alice")
+			test.ExpectEqual(t, values["gmid"], json.Number("1000000"))
+			test.ExpectEqual(t, len(values["summary_checkbox"].([]interface{})), 1)
+		})
+	})
+}
@@ -1,8 +1,11 @@
 package canvas
 
 import (
+	"sort"
+
 	"github.com/Blesmol/pfscf/pfscf/stamp"
 	"github.com/Blesmol/pfscf/pfscf/utils"
+	"github.com/Blesmol/pfscf/pfscf/yaml"
 )
 
 // Store stores a set of preset entries
@@ -79,15 +82,35 @@ func (s *Store) IsValid() (err error) {
 	return nil
 }
 
-// Resolve resolves inherited values between presets
+// Resolve resolves inherited values between canvases
 func (s *Store) Resolve() (err error) {
-	for _, entryPtr := range *s {
-		if err := entryPtr.resolve(s); err != nil {
-			return err
+	if errs := s.ResolveAll(); len(errs) > 0 {
+		return errs[0]
+	}
+	return nil
+}
+
+// ResolveAll resolves inherited values between canvases. In contrast to Resolve(), it
+// does not stop at the first error but returns all errors that were found. Each error
+// is of type *yaml.PathError and carries the ID of the affected canvas as path.
+func (s *Store) ResolveAll() (errs []error) {
+	for _, id := range s.getIDsSorted() {
+		if err := (*s)[id].resolve(s); err != nil {
+			errs = append(errs, yaml.NewPathError(err, id))
 		}
 	}
 
-	return nil
+	return errs
+}
+
+// getIDsSorted returns the IDs of all contained canvases in lexical order
+func (s *Store) getIDsSorted() (result []string) {
+	result = make([]string, 0, len(*s))
+	for id := range *s {
+		result = append(result, id)
+	}
+	sort.Strings(result)
+	return result
 }
 
 // AddCanvasesToStamp adds all included canvases to the provided stamp.
@@ -0,0 +1,130 @@
+package cfg
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/Blesmol/pfscf/pfscf/utils"
+	"github.com/Blesmol/pfscf/pfscf/yaml"
+)
+
+const (
+	userConfigFilename    = "config.yml"
+	projectConfigFilename = "pfscf.yml"
+)
+
+// ConfigFile contains default values for command line flags and for template
+// parameters. Flag values can either be single values or, for flags that can be
+// provided multiple times, lists of values.
+type ConfigFile struct {
+	Flags  map[string]interface{} `yaml:"flags"`
+	Values map[string]string      `yaml:"values"`
+
+	flagOrigins map[string]string // name of the file from which the flag was read
+}
+
+// newConfigFile returns an empty ConfigFile object
+func newConfigFile() (cf *ConfigFile) {
+	return &ConfigFile{
+		Flags:       make(map[string]interface{}),
+		Values:      make(map[string]string),
+		flagOrigins: make(map[string]string),
+	}
+}
+
+// GetConfigFilenames returns the list of config files that are read, ordered from
+// lowest to highest priority: The user-level config file in the user config dir, e.g.
+// $XDG_CONFIG_HOME/pfscf/config.yml on Linux, and the project-level config file
+// pfscf.yml in the current working directory.
+func GetConfigFilenames() (filenames []string) {
+	filenames = make([]string, 0)
+	if utils.IsTestEnvironment() {
+		return filenames
+	}
+
+	if userDir, err := getUserConfigDir(); err == nil {
+		filenames = append(filenames, filepath.Join(userDir, userConfigFilename))
+	}
+	if workDir, err := os.Getwd(); err == nil {
+		filenames = append(filenames, filepath.Join(workDir, projectConfigFilename))
+	}
+
+	return filenames
+}
+
+// ReadConfigFiles reads the provided config files and merges their content. Entries from
+// later files take precedence over entries from earlier files. Files that do not exist are
+// silently skipped.
+func ReadConfigFiles(filenames ...string) (cf *ConfigFile, err error) {
+	cf = newConfigFile()
+
+	for _, filename := range filenames {
+		if exists, _ := utils.IsFile(filename); !exists {
+			continue
+		}
+
+		fileCf := newConfigFile()
+		if err = yaml.ReadYamlFile(filename, fileCf); err != nil {
+			return nil, err
+		}
+
+		for flagName, value := range fileCf.Flags {
+			switch value.(type) {
+			case nil, map[interface{}]interface{}:
+				return nil, fmt.Errorf("Config file '%v': Flag '%v' must have a single value or a list of values", filename, flagName)
+			}
+			cf.Flags[flagName] = value
+			cf.flagOrigins[flagName] = filename
+		}
+		for key, value := range fileCf.Values {
+			cf.Values[key] = value
+		}
+	}
+
+	return cf, nil
+}
+
+// GetFlagValues returns the values configured for the provided flag. Multiple values are
+// returned in case a list was provided. Returns false if no value was configured.
+func (cf *ConfigFile) GetFlagValues(flagName string) (values []string, exists bool) {
+	value, exists := cf.Flags[flagName]
+	if !exists {
+		return nil, false
+	}
+
+	values = make([]string, 0)
+	if list, isList := value.([]interface{}); isList {
+		for _, entry := range list {
+			values = append(values, fmt.Sprint(entry))
+		}
+	} else {
+		values = append(values, fmt.Sprint(value))
+	}
+	return values, true
+}
+
+// GetFlagOrigin returns the name of the config file from which the value for the provided flag was read.
+func (cf *ConfigFile) GetFlagOrigin(flagName string) (filename string) {
+	return cf.flagOrigins[flagName]
+}
+
+// GetFlagNames returns the sorted list of names of all configured flags.
+func (cf *ConfigFile) GetFlagNames() (flagNames []string) {
+	flagNames = make([]string, 0, len(cf.Flags))
+	for flagName := range cf.Flags {
+		flagNames = append(flagNames, flagName)
+	}
+	sort.Strings(flagNames)
+	return flagNames
+}
+
+// getUserConfigDir returns the pfscf-specific subdirectory of the user config dir.
+func getUserConfigDir() (dir string, err error) {
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(configDir, appName), nil
+}
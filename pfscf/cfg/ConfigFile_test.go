@@ -0,0 +1,68 @@
+package cfg
+
+import (
+	"path/filepath"
+	"testing"
+
+	test "github.com/Blesmol/pfscf/pfscf/testutils"
+	"github.com/Blesmol/pfscf/pfscf/utils"
+)
+
+var (
+	cfgTestDir string
+)
+
+func init() {
+	utils.SetIsTestEnvironment(true)
+	cfgTestDir = filepath.Join(utils.GetExecutableDir(), "testdata")
+}
+
+func TestReadConfigFiles(t *testing.T) {
+	userFile := filepath.Join(cfgTestDir, "user.yml")
+	projectFile := filepath.Join(cfgTestDir, "project.yml")
+
+	t.Run("errors", func(t *testing.T) {
+		t.Run("flag with map value", func(t *testing.T) {
+			_, err := ReadConfigFiles(filepath.Join(cfgTestDir, "invalidFlag.yml"))
+			test.ExpectError(t, err, "Flag 'offset-x' must have a single value or a list of values")
+		})
+	})
+
+	t.Run("valid", func(t *testing.T) {
+		t.Run("no files", func(t *testing.T) {
+			cf, err := ReadConfigFiles(filepath.Join(cfgTestDir, "doesNotExist.yml"))
+			test.ExpectNoError(t, err)
+			test.ExpectEqual(t, len(cf.GetFlagNames()), 0)
+			test.ExpectEqual(t, len(cf.Values), 0)
+		})
+
+		t.Run("later files take precedence", func(t *testing.T) {
+			cf, err := ReadConfigFiles(userFile, projectFile)
+			test.ExpectNoError(t, err)
+
+			test.ExpectEqual(t, len(cf.GetFlagNames()), 3)
+
+			values, exists := cf.GetFlagValues("offset-x")
+			test.ExpectTrue(t, exists)
+			test.ExpectEqual(t, len(values), 1)
+			test.ExpectEqual(t, values[0], "-2")
+			test.ExpectEqual(t, cf.GetFlagOrigin("offset-x"), projectFile)
+
+			values, _ = cf.GetFlagValues("no-auto-open")
+			test.ExpectEqual(t, values[0], "true")
+			test.ExpectEqual(t, cf.GetFlagOrigin("no-auto-open"), userFile)
+
+			values, _ = cf.GetFlagValues("template-dir")
+			test.ExpectEqual(t, len(values), 2)
+			test.ExpectEqual(t, values[1], "dir2")
+
+			_, exists = cf.GetFlagValues("doesNotExist")
+			test.ExpectFalse(t, exists)
+
+			test.ExpectEqual(t, len(cf.Values), 3)
+			test.ExpectEqual(t, cf.Values["gmid"], "123456")
+			test.ExpectEqual(t, cf.Values["event"], "Project Event")
+			test.ExpectEqual(t, cf.Values["eventcode"], "4711")
+		})
+	})
+}
@@ -1,6 +1,8 @@
 package cfg
 
 import (
+	"fmt"
+	"os"
 	"path/filepath"
 
 	"github.com/Blesmol/pfscf/pfscf/utils"
@@ -8,10 +10,21 @@ import (
 
 const (
 	templateDir = "templates"
+	fontDir     = "fonts"
+	appName     = "pfscf"
+
+	// TemplateDirEnvVar is the name of the environment variable that can contain
+	// additional template directories, separated by the OS-specific list separator.
+	TemplateDirEnvVar = "PFSCF_TEMPLATE_DIR"
+
+	// FontDirEnvVar is the name of the environment variable that can contain
+	// additional font directories, separated by the OS-specific list separator.
+	FontDirEnvVar = "PFSCF_FONT_DIR"
 )
 
 var (
 	templateTestDir string
+	fontTestDir     string
 
 	// Global holds global config flags
 	Global globalFlags
@@ -24,9 +37,21 @@ type globalFlags struct {
 	DrawCanvas     bool
 	OffsetX        float64
 	OffsetY        float64
+	TemplateDirs   []string
 }
 
-// GetTemplatesDir returns the path below which the template files are stored.
+// TemplateDir describes a directory that contains template files along with the
+// information where this directory was configured.
+type TemplateDir struct {
+	Path   string
+	Origin string
+}
+
+func (td TemplateDir) String() string {
+	return fmt.Sprintf("%v (%v)", td.Path, td.Origin)
+}
+
+// GetTemplatesDir returns the path below which the bundled template files are stored.
 // In case a test environment is recognized, a different directory with testdata is returned.
 func GetTemplatesDir() (dir string) {
 	if utils.IsTestEnvironment() {
@@ -35,6 +60,59 @@ func GetTemplatesDir() (dir string) {
 	return getProductiveTemplatesDir()
 }
 
+// GetTemplateDirs returns the list of all directories from which templates should be read,
+// ordered from lowest to highest priority. Templates from a directory with a higher priority
+// override templates with the same ID from directories with a lower priority. The list consists of
+// - the templates directory next to the executable,
+// - the templates directory in the user config dir, if it exists,
+// - all directories listed in environment variable PFSCF_TEMPLATE_DIR,
+// - all directories provided via flag --template-dir.
+// In case a test environment is recognized, only the testing template directory is returned.
+func GetTemplateDirs() (dirs []TemplateDir, err error) {
+	if utils.IsTestEnvironment() {
+		return []TemplateDir{{Path: getTestingTemplatesDir(), Origin: "testing"}}, nil
+	}
+
+	dirs = []TemplateDir{{Path: getProductiveTemplatesDir(), Origin: "bundled"}}
+
+	if userDir, exists := getUserTemplatesDir(); exists {
+		dirs = append(dirs, TemplateDir{Path: userDir, Origin: "user"})
+	}
+
+	for _, dir := range filepath.SplitList(os.Getenv(TemplateDirEnvVar)) {
+		if utils.IsSet(dir) {
+			dirs = append(dirs, TemplateDir{Path: dir, Origin: "environment variable " + TemplateDirEnvVar})
+		}
+	}
+
+	for _, dir := range Global.TemplateDirs {
+		dirs = append(dirs, TemplateDir{Path: dir, Origin: "flag --template-dir"})
+	}
+
+	// only the user dir is optional, all explicitly configured dirs have to exist
+	for _, dir := range dirs {
+		if info, err := os.Stat(dir.Path); err != nil || !info.IsDir() {
+			return nil, fmt.Errorf("Template directory %v does not exist or is not a directory", dir)
+		}
+	}
+
+	return dirs, nil
+}
+
+// getUserTemplatesDir returns the path of the template directory within the
+// user-specific configuration dir, e.g. $XDG_CONFIG_HOME/pfscf/templates on Linux.
+// Returns false if no such directory exists.
+func getUserTemplatesDir() (dir string, exists bool) {
+	configDir, err := getUserConfigDir()
+	if err != nil {
+		return "", false
+	}
+
+	dir = filepath.Join(configDir, templateDir)
+	info, err := os.Stat(dir)
+	return dir, err == nil && info.IsDir()
+}
+
 // getProductiveTemplatesDir returns the path below which the
 // productive template files are stored.
 func getProductiveTemplatesDir() (dir string) {
@@ -52,3 +130,39 @@ func SetTestingTemplatesDir(dir string) {
 	utils.Assert(utils.IsTestEnvironment(), "Should only be called during tests")
 	templateTestDir = dir
 }
+
+// GetFontDirs returns the list of all existing directories in which font files are searched,
+// ordered from lowest to highest priority:
+// - the fonts directory next to the executable,
+// - the fonts directory in the user config dir,
+// - all directories listed in environment variable PFSCF_FONT_DIR.
+// In case a test environment is recognized, only the testing font directory is returned.
+func GetFontDirs() (dirs []string) {
+	candidates := make([]string, 0)
+	if utils.IsTestEnvironment() {
+		candidates = append(candidates, fontTestDir)
+	} else {
+		candidates = append(candidates, filepath.Join(utils.GetExecutableDir(), fontDir))
+		if configDir, err := getUserConfigDir(); err == nil {
+			candidates = append(candidates, filepath.Join(configDir, fontDir))
+		}
+		candidates = append(candidates, filepath.SplitList(os.Getenv(FontDirEnvVar))...)
+	}
+
+	dirs = make([]string, 0, len(candidates))
+	for _, dir := range candidates {
+		if !utils.IsSet(dir) {
+			continue
+		}
+		if info, err := os.Stat(dir); err == nil && info.IsDir() {
+			dirs = append(dirs, dir)
+		}
+	}
+	return dirs
+}
+
+// SetTestingFontsDir sets the global font dir in a testing environment
+func SetTestingFontsDir(dir string) {
+	utils.Assert(utils.IsTestEnvironment(), "Should only be called during tests")
+	fontTestDir = dir
+}
@@ -2,9 +2,13 @@ package cmd
 
 import (
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"regexp"
+	"runtime"
+	"sync"
+	"text/tabwriter"
 
 	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
@@ -19,6 +23,9 @@ import (
 
 const (
 	namingPlaceholderPattern = `<(\w+)>`
+
+	// exitCodePartialFailure is used if only some of the chronicles of a batch could be created
+	exitCodePartialFailure = 2
 )
 
 var (
@@ -31,6 +38,11 @@ var (
 	actionBatchInputChronicle string
 	actionBatchOutputDir      string
 
+	actionBatchFillJobs      int
+	actionBatchFillMergeFile string
+	actionBatchFillMergeOnly bool
+	actionBatchFillDryRun    bool
+
 	regexNamingPlaceholder = regexp.MustCompile(namingPlaceholderPattern)
 )
 
@@ -80,6 +92,10 @@ func GetBatchCommand() (cmd *cobra.Command) {
 	}
 	cmdFill.Flags().Float64VarP(&cfg.Global.OffsetX, "offset-x", "x", 0, "Assume an additional offset for the X axis of the chronicle")
 	cmdFill.Flags().Float64VarP(&cfg.Global.OffsetY, "offset-y", "y", 0, "Assume an additional offset for the Y axis of the chronicle")
+	cmdFill.Flags().IntVarP(&actionBatchFillJobs, "jobs", "j", runtime.NumCPU(), "Number of chronicles that are created in parallel")
+	cmdFill.Flags().StringVarP(&actionBatchFillMergeFile, "merge", "m", "", "Additionally combine all created chronicles into the provided PDF file, one page per player")
+	cmdFill.Flags().BoolVar(&actionBatchFillMergeOnly, "merge-only", false, "Only create the combined file provided with --merge, but no single file per player")
+	cmdFill.Flags().BoolVarP(&actionBatchFillDryRun, "dry-run", "", false, "Only check the CSV file and show what would be added to each chronicle, but do not create any files")
 
 	cmdBatch.AddCommand(cmdFill)
 
@@ -109,15 +125,13 @@ func executeBatchCreate(cmd *cobra.Command, cmdArgs []string) {
 
 	ts, err := template.GetStore()
 	utils.ExitOnError(err, "Error retrieving templates")
-	cTmpl, exists := ts.Get(tmplName)
-	if !exists {
-		utils.ExitWithMessage("Template '%v' not found", tmplName)
-	}
+	cTmpl, err := ts.Select(tmplName)
+	utils.ExitOnError(err, "Error retrieving template")
 
 	// parse remaining arguments
 	var argStore *args.Store
 	if !actionBatchCreateUsageExampleValues {
-		argStore, err = args.NewStore(args.StoreInit{Args: remainingArgs})
+		argStore, err = args.NewStore(args.StoreInit{Args: remainingArgs, Parent: getConfigArgStore(cTmpl)})
 	} else {
 		argStore, err = args.NewStore(args.StoreInit{Args: cTmpl.GetExampleArguments()})
 	}
@@ -169,13 +183,11 @@ func executeBatchFill(cmd *cobra.Command, cmdArgs []string) {
 	// get templates
 	ts, err := template.GetStore()
 	utils.ExitOnError(err, "Error retrieving templates")
-	cTmpl, exists := ts.Get(tmplName)
-	if !exists {
-		utils.ExitWithMessage("Template '%v' not found", tmplName)
-	}
+	cTmpl, err := ts.Select(tmplName)
+	utils.ExitOnError(err, "Error retrieving template")
 
 	// get arg value stores from CSV data
-	batchArgStores, err := args.GetArgStoresFromCsvRecords(csvRecords)
+	batchArgStores, columns, err := args.GetArgStoresAndColumnsFromCsvRecords(csvRecords)
 	utils.ExitOnError(err, "Error parsing CSV file")
 	if len(batchArgStores) == 0 {
 		utils.ExitWithMessage("No output files were created as CSV file '%v' does not contain any player values", inCsv)
@@ -185,25 +197,265 @@ func executeBatchFill(cmd *cobra.Command, cmdArgs []string) {
 	cmdLineArgStore, err := args.NewStore(args.StoreInit{Args: remainingArgs})
 	utils.ExitOnError(err, "Error processing command line arguments")
 
-	// ensure output directory exists
-	err = os.MkdirAll(outDir, os.ModePerm)
-	utils.ExitOnError(err, "Error creating output directory")
+	if actionBatchFillJobs < 1 {
+		utils.ExitWithMessage("Number of parallel jobs must be at least 1")
+	}
+
+	mergeFile := actionBatchFillMergeFile
+	if utils.IsSet(mergeFile) {
+		warnOnWrongFileExtension(mergeFile, "pdf")
+	} else if actionBatchFillMergeOnly {
+		utils.ExitWithMessage("Flag --merge-only requires flag --merge")
+	}
 
-	for idx, batchArgStore := range batchArgStores {
-		cmdLineArgStore.SetParent(batchArgStore) // command line arguments have priority
+	singleFileDir := outDir
+	switch {
+	case actionBatchFillDryRun:
+		// nothing is written during a dry run
+	case actionBatchFillMergeOnly:
+		// single files are only required as input for the merged file
+		singleFileDir = utils.GetTempDir()
+	default:
+		// ensure output directory exists
+		err = os.MkdirAll(outDir, os.ModePerm)
+		utils.ExitOnError(err, "Error creating output directory")
+	}
 
-		pdf, err := pdf.NewFile(inPdf)
+	// validate the values of all players before creating any chronicle
+	players := getBatchPlayers(cTmpl, batchArgStores, columns, cmdLineArgStore, singleFileDir)
+	numValid := 0
+	for _, player := range players {
+		if player.err != nil {
+			fmt.Fprintf(os.Stderr, "Skipping column %d: %v\n", player.column, player.err)
+		} else {
+			numValid++
+		}
+	}
+
+	if numValid > 0 {
+		// extract chronicle page only once for all players
+		inFile, err := pdf.NewFile(inPdf)
 		utils.ExitOnError(err, "Error opening input file '%v'", inPdf)
+		page, err := inFile.Prepare()
+		utils.ExitOnError(err, "Error preparing input file '%v'", inPdf)
+
+		if actionBatchFillDryRun {
+			dryRunBatch(page, cTmpl, players)
+		} else {
+			fmt.Printf("Creating %d file(s) using %d parallel job(s)\n", numValid, actionBatchFillJobs)
+			fillInParallel(page, cTmpl, players, actionBatchFillJobs)
+		}
+		page.Close()
+	}
+
+	if actionBatchFillDryRun {
+		if actionBatchFillMergeOnly {
+			for _, player := range players {
+				player.outfile = ""
+			}
+		}
+	} else if utils.IsSet(mergeFile) {
+		err = mergeAndCleanup(players, mergeFile, singleFileDir, actionBatchFillMergeOnly)
+		utils.ExitOnError(err, "Error creating merged file")
+	}
+
+	merged := utils.IsSet(mergeFile) && !actionBatchFillDryRun
+	numErrors := printBatchSummary(os.Stdout, players, merged, actionBatchFillDryRun)
+	if numErrors == len(players) {
+		utils.ExitWithMessage("Could not create any of the %d chronicle(s)", len(players))
+	} else if numErrors > 0 {
+		fmt.Fprintf(os.Stderr, "Error: Could not create %d of %d chronicle(s)\n", numErrors, len(players))
+		os.Exit(exitCodePartialFailure)
+	}
+}
+
+// batchPlayer contains everything that is required to create the chronicle for a single
+// player of a batch run, and the result of creating it.
+type batchPlayer struct {
+	column    int // column in the CSV file, starting at 1
+	argStore  *args.Store
+	outfile   string
+	mergePage int // page in the merged file, or 0 if not merged
+	err       error
+}
+
+// getBatchPlayers prepares the argument stores and output filenames for all players and
+// validates the arguments against the parameters of the provided template. Players with
+// problems are returned with their err field set.
+func getBatchPlayers(ct *template.Chronicle, batchArgStores []*args.Store, columns []int, cmdLineArgStore *args.Store, outDir string) (players []*batchPlayer) {
+	utils.Assert(len(batchArgStores) == len(columns), "Each player requires a CSV column")
+
+	configArgStore := getConfigArgStore(ct)
+	playerForOutfile := make(map[string]*batchPlayer)
+	players = make([]*batchPlayer, 0, len(batchArgStores))
+
+	for idx, batchArgStore := range batchArgStores {
+		player := &batchPlayer{column: columns[idx]}
+		players = append(players, player)
+
+		batchArgStore.SetParent(configArgStore) // values from config files have lowest priority
+		player.argStore, _ = args.NewStore(args.StoreInit{Parent: batchArgStore})
+		for _, key := range cmdLineArgStore.GetKeys() { // command line arguments have priority
+			value, _ := cmdLineArgStore.Get(key)
+			player.argStore.Set(key, value)
+		}
+
+		// validation adds entries to the provided store, so use a throw-away store for this
+		validationArgStore, _ := args.NewStore(args.StoreInit{Parent: player.argStore})
+		if player.err = ct.Parameters.ValidateAndProcessArgs(validationArgStore); player.err != nil {
+			continue
+		}
+
+		baseOutfile, err := getOutputFilenameForPlayer(actionBatchOutputPattern, player.argStore)
+		if err != nil {
+			player.err = fmt.Errorf("Error getting output filename: %v", err)
+			continue
+		}
+		player.outfile = filepath.Join(outDir, baseOutfile)
+
+		if otherPlayer, exists := playerForOutfile[player.outfile]; exists {
+			player.err = fmt.Errorf("Output file '%v' is already used for column %d", player.outfile, otherPlayer.column)
+			continue
+		}
+		playerForOutfile[player.outfile] = player
+	}
 
-		playerNumber := idx + 1
-		baseOutfile, err := getOutputFilenameForPlayer(actionBatchOutputPattern, cmdLineArgStore)
-		utils.ExitOnError(err, "Error getting output filename")
-		outfile := filepath.Join(outDir, baseOutfile)
+	return players
+}
 
-		fmt.Printf("Creating file %v\n", outfile)
-		err = pdf.Fill(cmdLineArgStore, cTmpl, outfile)
-		utils.ExitOnError(err, "Error when filling out chronicle for player %d", playerNumber)
+// fillInParallel fills the prepared page once for each valid player using the provided
+// number of parallel jobs. The result is stored in the err field of each player.
+func fillInParallel(page *pdf.PreparedPage, ct *template.Chronicle, players []*batchPlayer, numJobs int) {
+	validPlayers := make(chan *batchPlayer)
+
+	var wg sync.WaitGroup
+	for job := 0; job < numJobs; job++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for player := range validPlayers {
+				player.err = page.Fill(player.argStore, ct, player.outfile)
+			}
+		}()
 	}
+
+	for _, player := range players {
+		if player.err == nil {
+			validPlayers <- player
+		}
+	}
+	close(validPlayers)
+	wg.Wait()
+}
+
+// dryRunBatch prints for each valid player what would be added to the chronicle. Problems
+// are stored in the err field of the respective player.
+func dryRunBatch(page *pdf.PreparedPage, ct *template.Chronicle, players []*batchPlayer) {
+	for _, player := range players {
+		if player.err != nil {
+			continue
+		}
+
+		records, err := page.DryRun(player.argStore, ct)
+		if err != nil {
+			player.err = err
+			continue
+		}
+
+		if utils.IsSet(player.outfile) {
+			fmt.Printf("\nColumn %d: Would create file %v\n", player.column, player.outfile)
+		} else {
+			fmt.Printf("\nColumn %d:\n", player.column)
+		}
+		if numWarnings := printDryRunRecords(os.Stdout, records); numWarnings > 0 {
+			fmt.Printf("  %d text(s) would be shrunk to the minimum font size or truncated\n", numWarnings)
+		}
+	}
+}
+
+// mergeBatchOutput combines the files of all successfully created chronicles into a
+// single file, ordered by CSV column, and remembers the page number for each player.
+func mergeBatchOutput(players []*batchPlayer, mergeFile string) (err error) {
+	inFiles := make([]string, 0, len(players))
+	for _, player := range players {
+		if player.err == nil {
+			inFiles = append(inFiles, player.outfile)
+			player.mergePage = len(inFiles)
+		}
+	}
+	if len(inFiles) == 0 {
+		return nil
+	}
+
+	if err = pdf.MergeFiles(inFiles, mergeFile); err != nil {
+		for _, player := range players {
+			player.mergePage = 0
+		}
+		return err
+	}
+
+	fmt.Printf("Created merged file %v with %d page(s)\n", mergeFile, len(inFiles))
+	return nil
+}
+
+// mergeAndCleanup merges the created chronicles into the provided file. If only the merged
+// file is wanted, the directory with the single files is removed afterwards. If merging fails,
+// the single files are kept so that no chronicle gets lost.
+func mergeAndCleanup(players []*batchPlayer, mergeFile, singleFileDir string, mergeOnly bool) (err error) {
+	if err = mergeBatchOutput(players, mergeFile); err != nil {
+		if mergeOnly {
+			return fmt.Errorf("%v. The single chronicles were kept in directory '%v'", err, singleFileDir)
+		}
+		return err
+	}
+
+	if mergeOnly {
+		os.RemoveAll(singleFileDir)
+		for _, player := range players {
+			player.outfile = ""
+		}
+	}
+	return nil
+}
+
+// printBatchSummary prints a table that lists for each player whether the chronicle
+// was created or why this failed. If the chronicles were merged, then additionally the
+// page in the merged file is listed. Returns the number of failed players.
+func printBatchSummary(w io.Writer, players []*batchPlayer, merged, dryRun bool) (numErrors int) {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	if merged {
+		fmt.Fprintln(tw, "\nColumn\tFile\tPage\tResult")
+	} else {
+		fmt.Fprintln(tw, "\nColumn\tFile\tResult")
+	}
+	for _, player := range players {
+		outfile := player.outfile
+		if !utils.IsSet(outfile) {
+			outfile = "-"
+		}
+
+		result := "created"
+		if dryRun {
+			result = "would be created"
+		}
+		if player.err != nil {
+			result = fmt.Sprintf("failed: %v", player.err)
+			numErrors++
+		}
+
+		if merged {
+			page := "-"
+			if player.mergePage > 0 {
+				page = fmt.Sprint(player.mergePage)
+			}
+			fmt.Fprintf(tw, "%d\t%v\t%v\t%v\n", player.column, outfile, page, result)
+		} else {
+			fmt.Fprintf(tw, "%d\t%v\t%v\n", player.column, outfile, result)
+		}
+	}
+	tw.Flush()
+
+	return numErrors
 }
 
 func getOutputFilenameForPlayer(pattern string, as *args.Store) (outfile string, err error) {
@@ -0,0 +1,69 @@
+package cmd
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	test "github.com/Blesmol/pfscf/pfscf/testutils"
+	"github.com/Blesmol/pfscf/pfscf/utils"
+)
+
+// getBatchTestPlayers returns players whose chronicles were created in the provided directory.
+func getBatchTestPlayers(t *testing.T, singleFileDir string) (players []*batchPlayer) {
+	t.Helper()
+
+	content, err := ioutil.ReadFile(filepath.Join("..", "pdf", "testdata", "OnePage.pdf"))
+	test.ExpectNoError(t, err)
+
+	for idx := 1; idx <= 2; idx++ {
+		outfile := filepath.Join(singleFileDir, fmt.Sprintf("Chronicle_%d.pdf", idx))
+		test.ExpectNoError(t, ioutil.WriteFile(outfile, content, 0644))
+		players = append(players, &batchPlayer{column: idx + 1, outfile: outfile})
+	}
+	return players
+}
+
+func TestMergeAndCleanup(t *testing.T) {
+	workDir := utils.GetTempDir()
+	defer os.RemoveAll(workDir)
+
+	t.Run("errors", func(t *testing.T) {
+		singleFileDir := utils.GetTempDir()
+		defer os.RemoveAll(singleFileDir)
+		players := getBatchTestPlayers(t, singleFileDir)
+
+		mergeFile := filepath.Join(workDir, "nonExistantDir", "merged.pdf")
+		err := mergeAndCleanup(players, mergeFile, singleFileDir, true)
+		test.ExpectError(t, err, "Error merging files", "The single chronicles were kept in directory")
+
+		// the single files must survive a failed merge
+		for _, player := range players {
+			test.ExpectEqual(t, player.mergePage, 0)
+			test.ExpectTrue(t, utils.IsSet(player.outfile))
+			_, err := os.Stat(player.outfile)
+			test.ExpectNoError(t, err)
+		}
+	})
+
+	t.Run("valid", func(t *testing.T) {
+		singleFileDir := utils.GetTempDir()
+		defer os.RemoveAll(singleFileDir)
+		players := getBatchTestPlayers(t, singleFileDir)
+
+		mergeFile := filepath.Join(workDir, "merged.pdf")
+		err := mergeAndCleanup(players, mergeFile, singleFileDir, true)
+		test.ExpectNoError(t, err)
+
+		_, err = os.Stat(mergeFile)
+		test.ExpectNoError(t, err)
+		_, err = os.Stat(singleFileDir)
+		test.ExpectTrue(t, os.IsNotExist(err))
+		for idx, player := range players {
+			test.ExpectEqual(t, player.mergePage, idx+1)
+			test.ExpectEqual(t, player.outfile, "")
+		}
+	})
+}
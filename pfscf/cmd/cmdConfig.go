@@ -0,0 +1,86 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	"github.com/Blesmol/pfscf/pfscf/args"
+	"github.com/Blesmol/pfscf/pfscf/cfg"
+	"github.com/Blesmol/pfscf/pfscf/template"
+	"github.com/Blesmol/pfscf/pfscf/utils"
+)
+
+var (
+	configFile = &cfg.ConfigFile{}
+)
+
+// ApplyConfigFiles reads the user-level and project-level config files and uses
+// the contained flag values as defaults for all flags of the provided command that
+// were not explicitly set on the command line. It is intended to be used as
+// PersistentPreRun function of the root command.
+func ApplyConfigFiles(cmd *cobra.Command, cmdArgs []string) {
+	var err error
+	configFile, err = cfg.ReadConfigFiles(cfg.GetConfigFilenames()...)
+	utils.ExitOnError(err, "Error reading config file")
+
+	err = applyConfigFlags(cmd, configFile)
+	utils.ExitOnError(err, "Error applying config file")
+}
+
+// applyConfigFlags sets all flags of the provided command to the values from the config
+// file, unless they were explicitly set on the command line. The flags are afterwards
+// still marked as unchanged so that e.g. values from CSV files can take precedence.
+func applyConfigFlags(cmd *cobra.Command, cf *cfg.ConfigFile) (err error) {
+	for _, flagName := range cf.GetFlagNames() {
+		if !flagExistsInCommandTree(cmd.Root(), flagName) {
+			return fmt.Errorf("Unknown flag '%v' in config file '%v'", flagName, cf.GetFlagOrigin(flagName))
+		}
+
+		flag := cmd.Flags().Lookup(flagName)
+		if flag == nil || flag.Changed {
+			continue // flag belongs to another command or was set on the command line
+		}
+
+		values, _ := cf.GetFlagValues(flagName)
+		for _, value := range values {
+			if err = cmd.Flags().Set(flagName, value); err != nil {
+				return fmt.Errorf("Error setting flag '%v' with value '%v' from config file '%v': %v", flagName, value, cf.GetFlagOrigin(flagName), err)
+			}
+		}
+		flag.Changed = false
+	}
+
+	return nil
+}
+
+// flagExistsInCommandTree checks whether the provided command or any of its
+// subcommands has a flag with the provided name.
+func flagExistsInCommandTree(cmd *cobra.Command, flagName string) bool {
+	for _, flags := range []*pflag.FlagSet{cmd.Flags(), cmd.PersistentFlags()} {
+		if flags.Lookup(flagName) != nil {
+			return true
+		}
+	}
+
+	for _, subCmd := range cmd.Commands() {
+		if flagExistsInCommandTree(subCmd, flagName) {
+			return true
+		}
+	}
+	return false
+}
+
+// getConfigArgStore returns an argument store containing the default parameter values
+// from the config file. It is intended to be used as the parent with the lowest priority.
+// Values for arguments that are not used by the provided template are skipped.
+func getConfigArgStore(ct *template.Chronicle) (as *args.Store) {
+	as, _ = args.NewStore(args.StoreInit{InitCapacity: len(configFile.Values)})
+	for key, value := range configFile.Values {
+		if ct.Parameters.IsKnownArgument(key) {
+			as.Set(key, value)
+		}
+	}
+	return as
+}
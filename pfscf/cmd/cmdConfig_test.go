@@ -0,0 +1,47 @@
+package cmd
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Blesmol/pfscf/pfscf/args"
+	"github.com/Blesmol/pfscf/pfscf/cfg"
+	"github.com/Blesmol/pfscf/pfscf/template"
+	test "github.com/Blesmol/pfscf/pfscf/testutils"
+	"github.com/Blesmol/pfscf/pfscf/utils"
+
+	"gopkg.in/yaml.v2"
+)
+
+func TestGetConfigArgStore(t *testing.T) {
+	workDir := utils.GetTempDir()
+	defer os.RemoveAll(workDir)
+
+	// config files are shared between templates, so they may contain values
+	// for parameters that a template does not know
+	projectConfig := filepath.Join(workDir, "pfscf.yml")
+	test.ExpectNoError(t, ioutil.WriteFile(projectConfig, []byte("values:\n  gmid: 654321\n  unknown: foo\n"), 0644))
+
+	formerConfigFile := configFile
+	defer func() { configFile = formerConfigFile }()
+	var err error
+	configFile, err = cfg.ReadConfigFiles(projectConfig)
+	test.ExpectNoError(t, err)
+
+	var ct template.Chronicle
+	test.ExpectNoError(t, yaml.Unmarshal([]byte("group:\n  gmid:\n    type: text\n    example: 654321\n    description: GM ID\n"), &ct.Parameters))
+
+	configArgStore := getConfigArgStore(&ct)
+	value, exists := configArgStore.Get("gmid")
+	test.ExpectTrue(t, exists)
+	test.ExpectEqual(t, value, "654321")
+	_, exists = configArgStore.Get("unknown")
+	test.ExpectFalse(t, exists)
+
+	// the unknown value must not prevent filling out the chronicle
+	as, err := args.NewStore(args.StoreInit{Parent: configArgStore})
+	test.ExpectNoError(t, err)
+	test.ExpectNoError(t, ct.Parameters.ValidateAndProcessArgs(as))
+}
@@ -2,6 +2,7 @@ package cmd
 
 import (
 	"fmt"
+	"os"
 
 	"github.com/spf13/cobra"
 
@@ -15,6 +16,8 @@ import (
 var (
 	cmdFillUseExampleValues    bool
 	cmdFillSuppressOpenOutfile bool
+	cmdFillValuesFile          string
+	cmdFillDryRun              bool
 )
 
 // GetFillCommand returns the cobra command for the "fill" action.
@@ -34,7 +37,9 @@ func GetFillCommand() (cmd *cobra.Command) {
 	fillCmd.Flags().BoolVarP(&cfg.Global.DrawCellBorder, "cell-border", "c", false, "Draw the cell borders of all added fields")
 	fillCmd.Flags().BoolVarP(&cmdFillUseExampleValues, "examples", "e", false, "Use example values to fill out the chronicle")
 	fillCmd.Flags().BoolVarP(&cmdFillSuppressOpenOutfile, "no-auto-open", "n", false, "Suppress auto-opening the filled out chronicle")
+	fillCmd.Flags().StringVarP(&cmdFillValuesFile, "values", "", "", "YAML or JSON file with parameter values. Values provided on the command line take precedence")
 	fillCmd.Flags().BoolVarP(&cfg.Global.DrawCanvas, "draw-canvas", "d", false, "Draw a border around all defined canvases")
+	fillCmd.Flags().BoolVarP(&cmdFillDryRun, "dry-run", "", false, "Only check the arguments and show what would be added to the chronicle, but do not create the output file")
 	fillCmd.Flags().Float64VarP(&cfg.Global.OffsetX, "offset-x", "x", 0, "Assume an additional offset for the X axis of the chronicle")
 	fillCmd.Flags().Float64VarP(&cfg.Global.OffsetY, "offset-y", "y", 0, "Assume an additional offset for the Y axis of the chronicle")
 
@@ -55,17 +60,22 @@ func executeFill(cmd *cobra.Command, cmdArgs []string) {
 	warnOnWrongFileExtension(inFile, "pdf")
 	warnOnWrongFileExtension(outFile, "pdf")
 
+	if cmdFillUseExampleValues && utils.IsSet(cmdFillValuesFile) {
+		utils.ExitWithMessage("Flags --examples and --values cannot be used together")
+	}
+
 	ts, err := template.GetStore()
 	utils.ExitOnError(err, "Error retrieving templates")
-	cTmpl, exists := ts.Get(tmplName)
-	if !exists {
-		utils.ExitWithMessage("Template '%v' not found", tmplName)
-	}
+	cTmpl, err := ts.Select(tmplName)
+	utils.ExitOnError(err, "Error retrieving template")
 
 	// parse remaining arguments
 	var argStore *args.Store
 	if !cmdFillUseExampleValues {
-		argStore, err = args.NewStore(args.StoreInit{Args: cmdArgs[3:]})
+		var valuesArgStore *args.Store
+		valuesArgStore, err = getValuesArgStore(cmdFillValuesFile, cTmpl, getConfigArgStore(cTmpl))
+		utils.ExitOnError(err, "Error processing values file")
+		argStore, err = args.NewStore(args.StoreInit{Args: cmdArgs[3:], Parent: valuesArgStore})
 	} else {
 		argStore, err = args.NewStore(args.StoreInit{Args: cTmpl.GetExampleArguments()})
 	}
@@ -74,6 +84,11 @@ func executeFill(cmd *cobra.Command, cmdArgs []string) {
 	pf, err := pdf.NewFile(inFile)
 	utils.ExitOnError(err, "Error opening input file '%v'", inFile)
 
+	if cmdFillDryRun {
+		executeFillDryRun(pf, argStore, cTmpl, outFile)
+		return
+	}
+
 	err = pf.Fill(argStore, cTmpl, outFile)
 	utils.ExitOnError(err, "Error when filling out chronicle")
 
@@ -83,3 +98,18 @@ func executeFill(cmd *cobra.Command, cmdArgs []string) {
 		utils.ExitOnError(err, "Error opening PDF file")
 	}
 }
+
+// executeFillDryRun prints what would be added to the output file, without creating it.
+func executeFillDryRun(pf *pdf.File, argStore *args.Store, ct *template.Chronicle, outFile string) {
+	page, err := pf.Prepare()
+	utils.ExitOnError(err, "Error preparing input file")
+	records, err := page.DryRun(argStore, ct)
+	page.Close()
+	utils.ExitOnError(err, "Error when filling out chronicle")
+
+	fmt.Printf("Would create file %v\n", outFile)
+	numWarnings := printDryRunRecords(os.Stdout, records)
+	if numWarnings > 0 {
+		fmt.Printf("%d text(s) would be shrunk to the minimum font size or truncated\n", numWarnings)
+	}
+}
@@ -2,14 +2,26 @@ package cmd
 
 import (
 	"fmt"
+	"os"
+	"strings"
 
 	"github.com/spf13/cobra"
 
 	"github.com/Blesmol/pfscf/pfscf/cfg"
 	"github.com/Blesmol/pfscf/pfscf/template"
+	"github.com/Blesmol/pfscf/pfscf/update"
 	"github.com/Blesmol/pfscf/pfscf/utils"
 )
 
+const (
+	templateSourceEnvVar = "PFSCF_TEMPLATE_SOURCE"
+)
+
+var (
+	cmdTemplateUpdateSource   string
+	cmdTemplateUpdateRollback bool
+)
+
 // GetTemplateCommand returns the cobra command for the "fill" action.
 func GetTemplateCommand() (cmd *cobra.Command) {
 	templateCmd := &cobra.Command{
@@ -61,33 +73,35 @@ func GetTemplateCommand() (cmd *cobra.Command) {
 	}
 	templateCmd.AddCommand(templateSearchCmd)
 
-	/*
-		templateValidateCmd := &cobra.Command{
-			Use:     "validate <template>",
-			Aliases: []string{"v"},
+	templateValidateCmd := &cobra.Command{
+		Use:     "validate [<template> ...]",
+		Aliases: []string{"v"},
 
-			Short: "Validate a specific template",
-			//Long:  "TBD",
+		Short: "Validate all or specific templates",
+		Long:  "Validate all template files, or only the templates with the provided IDs. All problems found are listed along with the file name and line number. Returns with a non-zero exit code if at least one problem was found.",
 
-			Args: cobra.ExactArgs(1),
+		Args: cobra.ArbitraryArgs,
 
-			Run: executeTemplateValidate,
-		}
-		templateCmd.AddCommand(templateValidateCmd)
-	*/
+		Run: executeTemplateValidate,
+	}
+	templateCmd.AddCommand(templateValidateCmd)
 
-	/*
-		templateUpdateCmd := &cobra.Command{
-			Use:     "update",
-			Aliases: []string{"u"},
+	templateUpdateCmd := &cobra.Command{
+		Use:     "update",
+		Aliases: []string{"u"},
 
-			Short: "Update the locally available templates",
-			Long:  "Update the locally available templates with the latest templates from the central github repository",
+		Short: "Update the locally available templates",
+		Long: "Update the locally available templates from a template bundle. The bundle can be a directory or a .zip, .tar, .tar.gz or .tgz file, " +
+			"and it can be provided as local path or as HTTP(S) URL. The bundle has to contain a checksum manifest '" + update.ManifestFilename + "' " +
+			"in the format of the 'sha256sum' tool. The previously installed templates are kept and can be restored with flag --rollback.",
 
-			Run: executeTemplateUpdate,
-		}
-		templateCmd.AddCommand(templateUpdateCmd)
-	*/
+		Args: cobra.ExactArgs(0),
+
+		Run: executeTemplateUpdate,
+	}
+	templateUpdateCmd.Flags().StringVarP(&cmdTemplateUpdateSource, "source", "s", "", "Location of the template bundle. Defaults to the value of environment variable "+templateSourceEnvVar)
+	templateUpdateCmd.Flags().BoolVarP(&cmdTemplateUpdateRollback, "rollback", "", false, "Restore the templates that were installed before the last update")
+	templateCmd.AddCommand(templateUpdateCmd)
 
 	return templateCmd
 }
@@ -98,6 +112,21 @@ func executeTemplateList(cmd *cobra.Command, args []string) {
 
 	fmt.Printf("List of available templates:\n\n")
 	fmt.Printf(ts.ListTemplates())
+
+	if overrides := ts.Overrides(); len(overrides) > 0 {
+		fmt.Printf("\nThe following templates are defined in multiple template directories:\n")
+		for _, override := range overrides {
+			fmt.Printf("- %v: Using '%v', overriding '%v'\n", override.TemplateID, override.Filename, strings.Join(override.Overridden, "', '"))
+		}
+	}
+
+	if problems := ts.Problems(); len(problems) > 0 {
+		fmt.Fprintf(os.Stderr, "\nWarning: The following problem(s) were found, affected templates are not listed above:\n")
+		for _, problem := range problems {
+			fmt.Fprintf(os.Stderr, "- %v\n", problem)
+		}
+		fmt.Fprintf(os.Stderr, "Use 'pfscf template validate' to check templates for problems\n")
+	}
 }
 
 func executeTemplateDescribe(cmd *cobra.Command, args []string) {
@@ -106,10 +135,8 @@ func executeTemplateDescribe(cmd *cobra.Command, args []string) {
 	ts, err := template.GetStore()
 	utils.ExitOnError(err, "Could not read templates")
 
-	ct, exists := ts.Get(templateName)
-	if !exists {
-		utils.ExitWithMessage("Coud not find template '%v'", templateName)
-	}
+	ct, err := ts.Select(templateName)
+	utils.ExitOnError(err, "Error retrieving template")
 
 	fmt.Printf("Template '%v'\n\n", templateName)
 	fmt.Printf(ct.DescribeParams(cfg.Global.Verbose))
@@ -128,9 +155,51 @@ func executeTemplateSearch(cmd *cobra.Command, args []string) {
 }
 
 func executeTemplateValidate(cmd *cobra.Command, args []string) {
-	fmt.Println("Not yet implemented")
+	problems, err := template.Validate(args...)
+	utils.ExitOnError(err, "Could not validate templates")
+
+	if len(problems) == 0 {
+		fmt.Println("No problems found")
+		return
+	}
+
+	for _, problem := range problems {
+		fmt.Println(problem.Error())
+	}
+	utils.ExitWithMessage("Found %d problem(s)", len(problems))
 }
 
 func executeTemplateUpdate(cmd *cobra.Command, args []string) {
-	fmt.Println("Not yet implemented")
+	templatesDir := cfg.GetTemplatesDir()
+
+	if cmdTemplateUpdateRollback {
+		if utils.IsSet(cmdTemplateUpdateSource) {
+			utils.ExitWithMessage("Flags --source and --rollback cannot be used together")
+		}
+		err := update.Rollback(templatesDir)
+		utils.ExitOnError(err, "Could not restore previous templates")
+		fmt.Printf("Restored previous templates in '%v'\n", templatesDir)
+		return
+	}
+
+	source := cmdTemplateUpdateSource
+	if !utils.IsSet(source) {
+		source = os.Getenv(templateSourceEnvVar)
+	}
+	if !utils.IsSet(source) {
+		utils.ExitWithMessage("No template source provided, use flag --source or set environment variable %v", templateSourceEnvVar)
+	}
+
+	numFiles, err := update.Update(source, templatesDir)
+	utils.ExitOnError(err, "Could not update templates")
+	fmt.Printf("Installed %d file(s) from '%v' into '%v'\n", numFiles, source, templatesDir)
+	if update.HasPreviousVersion(templatesDir) {
+		fmt.Printf("Previous templates were kept in '%v'\n", update.GetPreviousDir(templatesDir))
+	}
+
+	ts, err := template.GetStore()
+	utils.ExitOnError(err, "Could not read templates")
+	if numProblems := len(ts.Problems()); numProblems > 0 {
+		fmt.Fprintf(os.Stderr, "Warning: Found %d problem(s) in the new templates, use 'pfscf template validate' for details\n", numProblems)
+	}
 }
@@ -2,14 +2,47 @@ package cmd
 
 import (
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"strings"
+	"text/tabwriter"
 
+	"github.com/Blesmol/pfscf/pfscf/args"
+	"github.com/Blesmol/pfscf/pfscf/stamp"
+	"github.com/Blesmol/pfscf/pfscf/template"
 	"github.com/Blesmol/pfscf/pfscf/utils"
 	"github.com/spf13/cobra"
 )
 
+// getValuesArgStore reads the parameter values from the provided file and returns them
+// in an argument store with the provided parent. If no filename is provided, then the
+// parent is returned.
+func getValuesArgStore(filename string, ct *template.Chronicle, parent *args.Store) (as *args.Store, err error) {
+	if !utils.IsSet(filename) {
+		return parent, nil
+	}
+
+	values, err := args.ReadValuesFile(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	valueArgs, err := ct.Parameters.GetArgsFromValues(values)
+	if err != nil {
+		return nil, fmt.Errorf("File '%v': %v", filename, err)
+	}
+
+	as, err = args.NewStore(args.StoreInit{InitCapacity: len(valueArgs), Parent: parent})
+	if err != nil {
+		return nil, err
+	}
+	for key, value := range valueArgs {
+		as.Set(key, value)
+	}
+	return as, nil
+}
+
 func warnOnWrongFileExtension(filename, expectedExt string) {
 	realExt := strings.ToLower(filepath.Ext(filename))
 	if realExt != strings.ToLower("."+expectedExt) {
@@ -53,3 +86,35 @@ func setFlagsFromRecords(cmd *cobra.Command, records [][]string) error {
 
 	return nil
 }
+
+// printDryRunRecords prints a table that lists which content entry would add which
+// value on which canvas. Text that would be shrunk to the minimum font size or that would
+// be truncated is marked. Returns the number of texts that were marked.
+func printDryRunRecords(w io.Writer, records []stamp.Record) (numWarnings int) {
+	if len(records) == 0 {
+		fmt.Fprintln(w, "  No content would be added")
+		return 0
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "  Content\tType\tCanvas\tValue")
+	for _, record := range records {
+		value := "-"
+		if record.Type == "text" || record.Type == "paragraph" || record.Type == "image" || record.Type == "mark" {
+			value = fmt.Sprintf("%q", record.Value)
+		}
+
+		fmt.Fprintf(tw, "  %v\t%v\t%v\t%v", record.Content, record.Type, record.Canvas, value)
+		if record.Truncated {
+			fmt.Fprintf(tw, "\tWARNING: Text would be truncated, even with minimum font size %v", record.Fontsize)
+			numWarnings++
+		} else if record.Shrunk {
+			fmt.Fprintf(tw, "\tWARNING: Text would be shrunk to minimum font size %v", record.Fontsize)
+			numWarnings++
+		}
+		fmt.Fprintln(tw)
+	}
+	tw.Flush()
+
+	return numWarnings
+}
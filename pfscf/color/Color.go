@@ -0,0 +1,104 @@
+package color
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var (
+	regexHexColor    = regexp.MustCompile(`^#([0-9a-f]{3}|[0-9a-f]{6}|[0-9a-f]{8})$`)
+	regexLegacyColor = regexp.MustCompile(`^[0-9a-f]{6}$`)
+	regexRGBColor    = regexp.MustCompile(`^rgba?\(([^)]*)\)$`)
+)
+
+// Color is a color with red, green and blue components between 0 and 255 and
+// an alpha value between 0.0 (invisible) and 1.0 (opaque).
+type Color struct {
+	R, G, B int
+	A       float64
+}
+
+// Parse parses the provided color value. Supported are the CSS color names, hex
+// codes in the forms #rgb, #rrggbb and #rrggbbaa, hex codes rrggbb without leading
+// '#', and the notations rgb(r, g, b) and rgba(r, g, b, a). Color names and hex
+// codes are case-insensitive.
+func Parse(value string) (c Color, err error) {
+	value = strings.ToLower(strings.TrimSpace(value))
+
+	if c, exists := cssColors[value]; exists {
+		return c, nil
+	}
+
+	if regexLegacyColor.MatchString(value) {
+		return parseHex(value)
+	}
+
+	if match := regexHexColor.FindStringSubmatch(value); match != nil {
+		return parseHex(match[1])
+	}
+
+	if match := regexRGBColor.FindStringSubmatch(value); match != nil {
+		return parseRGB(match[1])
+	}
+
+	return c, fmt.Errorf("Unknown color: '%v'", value)
+}
+
+// parseHex parses a hex code with 3, 6 or 8 digits. The regular expressions already
+// ensure that only valid hex digits are contained.
+func parseHex(code string) (c Color, err error) {
+	if len(code) == 3 {
+		code = string([]byte{code[0], code[0], code[1], code[1], code[2], code[2]})
+	}
+
+	component := func(idx int) int {
+		value, _ := strconv.ParseUint(code[2*idx:2*idx+2], 16, 8)
+		return int(value)
+	}
+
+	c = Color{R: component(0), G: component(1), B: component(2), A: 1.0}
+	if len(code) == 8 {
+		c.A = float64(component(3)) / 255.0
+	}
+	return c, nil
+}
+
+// parseRGB parses the comma-separated arguments of the rgb() or rgba() notation.
+// The color components are integers between 0 and 255, the optional alpha value
+// is a number between 0.0 and 1.0.
+func parseRGB(arguments string) (c Color, err error) {
+	parts := strings.Split(arguments, ",")
+	if len(parts) != 3 && len(parts) != 4 {
+		return c, fmt.Errorf("Color in rgb notation requires 3 or 4 values, found %v: 'rgb(%v)'", len(parts), arguments)
+	}
+
+	components := make([]int, 3)
+	for idx := range components {
+		part := strings.TrimSpace(parts[idx])
+		if components[idx], err = strconv.Atoi(part); err != nil || components[idx] < 0 || components[idx] > 255 {
+			return c, fmt.Errorf("Color component '%v' is not an integer between 0 and 255", part)
+		}
+	}
+
+	c = Color{R: components[0], G: components[1], B: components[2], A: 1.0}
+	if len(parts) == 4 {
+		part := strings.TrimSpace(parts[3])
+		if c.A, err = strconv.ParseFloat(part, 64); err != nil || c.A < 0.0 || c.A > 1.0 {
+			return Color{}, fmt.Errorf("Alpha value '%v' is not a number between 0.0 and 1.0", part)
+		}
+	}
+
+	return c, nil
+}
+
+// RGB returns the red, green and blue components of the color.
+func (c Color) RGB() (r, g, b int) {
+	return c.R, c.G, c.B
+}
+
+// Transparency returns how transparent the color is, from 0.0 (opaque) to 1.0 (invisible).
+func (c Color) Transparency() float64 {
+	return 1.0 - c.A
+}
@@ -0,0 +1,75 @@
+package color
+
+import (
+	"testing"
+
+	test "github.com/Blesmol/pfscf/pfscf/testutils"
+)
+
+func TestParse(t *testing.T) {
+	t.Run("errors", func(t *testing.T) {
+		t.Run("unknown color", func(t *testing.T) {
+			for _, value := range []string{"", "paizo_red", "#ff", "#ffcc0", "#ffcc00f", "ffcc", "#gggggg", "rgb 1, 2, 3"} {
+				_, err := Parse(value)
+				test.ExpectError(t, err, "Unknown color")
+			}
+		})
+
+		t.Run("wrong number of values", func(t *testing.T) {
+			for _, value := range []string{"rgb()", "rgb(1, 2)", "rgba(1, 2, 3, 4, 5)"} {
+				_, err := Parse(value)
+				test.ExpectError(t, err, "rgb notation requires 3 or 4 values")
+			}
+		})
+
+		t.Run("invalid component", func(t *testing.T) {
+			for _, value := range []string{"rgb(256, 0, 0)", "rgb(-1, 0, 0)", "rgb(1.5, 0, 0)", "rgb(red, 0, 0)"} {
+				_, err := Parse(value)
+				test.ExpectError(t, err, "is not an integer between 0 and 255")
+			}
+		})
+
+		t.Run("invalid alpha", func(t *testing.T) {
+			for _, value := range []string{"rgba(0, 0, 0, 1.5)", "rgba(0, 0, 0, -0.5)", "rgba(0, 0, 0, x)"} {
+				_, err := Parse(value)
+				test.ExpectError(t, err, "is not a number between 0.0 and 1.0")
+			}
+		})
+	})
+
+	t.Run("valid", func(t *testing.T) {
+		for _, tc := range []struct {
+			value    string
+			expected Color
+		}{
+			{"black", Color{0, 0, 0, 1.0}},
+			{"White", Color{255, 255, 255, 1.0}},
+			{" green ", Color{0, 128, 0, 1.0}},
+			{"rebeccapurple", Color{102, 51, 153, 1.0}},
+			{"transparent", Color{0, 0, 0, 0.0}},
+			{"a12b2b", Color{161, 43, 43, 1.0}},
+			{"#A12B2B", Color{161, 43, 43, 1.0}},
+			{"#fc0", Color{255, 204, 0, 1.0}},
+			{"#ffcc0000", Color{255, 204, 0, 0.0}},
+			{"#ffcc00ff", Color{255, 204, 0, 1.0}},
+			{"rgb(161, 43, 43)", Color{161, 43, 43, 1.0}},
+			{"rgb(161,43,43,0.5)", Color{161, 43, 43, 0.5}},
+			{"rgba(0, 0, 255, 0.25)", Color{0, 0, 255, 0.25}},
+		} {
+			c, err := Parse(tc.value)
+			test.ExpectNoError(t, err)
+			test.ExpectEqual(t, c, tc.expected)
+		}
+	})
+}
+
+func TestColor_Transparency(t *testing.T) {
+	c, err := Parse("rgba(0, 0, 0, 0.25)")
+	test.ExpectNoError(t, err)
+	test.ExpectEqual(t, c.Transparency(), 0.75)
+
+	r, g, b := Color{1, 2, 3, 1.0}.RGB()
+	test.ExpectEqual(t, r, 1)
+	test.ExpectEqual(t, g, 2)
+	test.ExpectEqual(t, b, 3)
+}
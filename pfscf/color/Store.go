@@ -0,0 +1,56 @@
+package color
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/Blesmol/pfscf/pfscf/yaml"
+)
+
+// Store stores a set of named colors, e.g. from the 'colors' section of a template.
+// The key is the name of the color and the value its definition.
+type Store map[string]string
+
+// NewStore creates a new store.
+func NewStore() (s Store) {
+	s = make(Store, 0)
+	return s
+}
+
+// InheritFrom copies over colors from another Store that do not yet exist
+// in the current Store.
+func (s *Store) InheritFrom(other Store) {
+	for name, value := range other {
+		if _, exists := (*s)[name]; !exists {
+			(*s)[name] = value
+		}
+	}
+}
+
+// ValidateAll checks whether all contained colors have a valid definition and returns
+// all errors found. Each error is of type *yaml.PathError and carries the name of the
+// affected color as path.
+func (s Store) ValidateAll() (errs []error) {
+	names := make([]string, 0, len(s))
+	for name := range s {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		if _, err := Parse(s[name]); err != nil {
+			errs = append(errs, yaml.NewPathError(err, name))
+		}
+	}
+
+	return errs
+}
+
+// Resolve returns the definition of the named color if the provided value is the name
+// of a color in the store. Otherwise the value is returned unchanged.
+func (s Store) Resolve(value string) string {
+	if definition, exists := s[strings.TrimSpace(value)]; exists {
+		return definition
+	}
+	return value
+}
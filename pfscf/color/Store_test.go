@@ -0,0 +1,52 @@
+package color
+
+import (
+	"testing"
+
+	test "github.com/Blesmol/pfscf/pfscf/testutils"
+)
+
+func TestStore_InheritFrom(t *testing.T) {
+	parent := NewStore()
+	parent["highlight"] = "yellow"
+	parent["paizo_red"] = "#a12b2b"
+
+	child := NewStore()
+	child["highlight"] = "orange"
+
+	child.InheritFrom(parent)
+	test.ExpectEqual(t, len(child), 2)
+	test.ExpectEqual(t, child["highlight"], "orange")
+	test.ExpectEqual(t, child["paizo_red"], "#a12b2b")
+}
+
+func TestStore_ValidateAll(t *testing.T) {
+	t.Run("errors", func(t *testing.T) {
+		s := NewStore()
+		s["valid"] = "red"
+		s["invalid1"] = "reddish"
+		s["invalid2"] = "#12345"
+
+		errs := s.ValidateAll()
+		test.ExpectEqual(t, len(errs), 2)
+		test.ExpectError(t, errs[0], "Unknown color: 'reddish'")
+		test.ExpectError(t, errs[1], "Unknown color: '#12345'")
+	})
+
+	t.Run("valid", func(t *testing.T) {
+		s := NewStore()
+		s["highlight"] = "rgba(255, 204, 0, 0.5)"
+
+		test.ExpectEqual(t, len(s.ValidateAll()), 0)
+	})
+}
+
+func TestStore_Resolve(t *testing.T) {
+	s := NewStore()
+	s["paizo_red"] = "#a12b2b"
+
+	test.ExpectEqual(t, s.Resolve("paizo_red"), "#a12b2b")
+	test.ExpectEqual(t, s.Resolve(" paizo_red "), "#a12b2b")
+	test.ExpectEqual(t, s.Resolve("red"), "red")
+	test.ExpectEqual(t, s.Resolve(""), "")
+}
@@ -5,6 +5,7 @@ import (
 
 	"github.com/Blesmol/pfscf/pfscf/args"
 	"github.com/Blesmol/pfscf/pfscf/canvas"
+	"github.com/Blesmol/pfscf/pfscf/color"
 	"github.com/Blesmol/pfscf/pfscf/param"
 	"github.com/Blesmol/pfscf/pfscf/preset"
 	"github.com/Blesmol/pfscf/pfscf/stamp"
@@ -13,7 +14,7 @@ import (
 // Entry is an interface for the content. D'oh!
 type Entry interface {
 	isValid(*param.Store, *canvas.Store) (err error)
-	resolve(ps preset.Store) (err error)
+	resolve(ps preset.Store, cs color.Store) (err error)
 	generateOutput(s *stamp.Stamp, as *args.Store) (err error)
 	deepCopy() Entry
 }
@@ -4,6 +4,7 @@ import (
 	"testing"
 
 	"github.com/Blesmol/pfscf/pfscf/args"
+	"github.com/Blesmol/pfscf/pfscf/color"
 	"github.com/Blesmol/pfscf/pfscf/preset"
 	test "github.com/Blesmol/pfscf/pfscf/testutils"
 	"gopkg.in/yaml.v2"
@@ -29,6 +30,12 @@ conflict2:
 	return &store
 }
 
+func getTestColorStore() (cs *color.Store) {
+	store := color.NewStore()
+	store["highlight"] = "#ffcc00"
+	return &store
+}
+
 func getTestArgStore(key, value string) (as *args.Store) {
 	as, _ = args.NewStore(args.StoreInit{})
 	as.Set(key, value)
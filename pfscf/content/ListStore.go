@@ -1,11 +1,15 @@
 package content
 
 import (
+	"strconv"
+
 	"github.com/Blesmol/pfscf/pfscf/args"
 	"github.com/Blesmol/pfscf/pfscf/canvas"
+	"github.com/Blesmol/pfscf/pfscf/color"
 	"github.com/Blesmol/pfscf/pfscf/param"
 	"github.com/Blesmol/pfscf/pfscf/preset"
 	"github.com/Blesmol/pfscf/pfscf/stamp"
+	"github.com/Blesmol/pfscf/pfscf/yaml"
 )
 
 // ListStore stores a list of parameter descriptions
@@ -28,10 +32,20 @@ func (s *ListStore) InheritFrom(other ListStore) {
 	}
 }
 
-// Resolve resolves preset requirements for all entries in the ContentStore
-func (s *ListStore) Resolve(ps preset.Store) (err error) {
+// SetBaseDir sets the directory against which relative filenames from the entries are resolved.
+// This is usually the directory of the template file that defines the entries.
+func (s *ListStore) SetBaseDir(dir string) {
+	for _, entry := range *s {
+		if e, isImage := entry.(*image); isImage {
+			e.baseDir = dir
+		}
+	}
+}
+
+// Resolve resolves preset requirements and named colors for all entries in the ContentStore
+func (s *ListStore) Resolve(ps preset.Store, cs color.Store) (err error) {
 	for _, entry := range *s {
-		if err := entry.resolve(ps); err != nil {
+		if err := entry.resolve(ps, cs); err != nil {
 			return err
 		}
 	}
@@ -39,6 +53,19 @@ func (s *ListStore) Resolve(ps preset.Store) (err error) {
 	return nil
 }
 
+// ResolveAll resolves preset requirements and named colors for all entries in the ContentStore. In contrast
+// to Resolve(), it does not stop at the first error but returns all errors that were found.
+// Each error is of type *yaml.PathError and carries the list index of the affected entry as path.
+func (s *ListStore) ResolveAll(ps preset.Store, cs color.Store) (errs []error) {
+	for idx, entry := range *s {
+		if err := entry.resolve(ps, cs); err != nil {
+			errs = append(errs, yaml.NewPathError(err, strconv.Itoa(idx)))
+		}
+	}
+
+	return errs
+}
+
 // UnmarshalYAML unmarshals a Content List Store
 func (s *ListStore) UnmarshalYAML(unmarshal func(interface{}) error) (err error) {
 	type storeYAML []entryYAML
@@ -60,8 +87,11 @@ func (s *ListStore) UnmarshalYAML(unmarshal func(interface{}) error) (err error)
 
 // GenerateOutput generates the output for the current content store into the provided stamp
 func (s *ListStore) GenerateOutput(stamp *stamp.Stamp, argStore *args.Store) (err error) {
-	for _, entry := range *s {
-		if err = entry.generateOutput(stamp, argStore); err != nil {
+	for idx, entry := range *s {
+		stamp.EnterContent(strconv.Itoa(idx))
+		err = entry.generateOutput(stamp, argStore)
+		stamp.LeaveContent()
+		if err != nil {
 			return err
 		}
 	}
@@ -80,6 +110,18 @@ func (s *ListStore) IsValid(paramStore *param.Store, canvasStore *canvas.Store)
 	return nil
 }
 
+// ValidateAll validates all content entries. In contrast to IsValid(), it does not stop at
+// the first error but returns all errors that were found. Each error is of type *yaml.PathError
+// and carries the list index of the affected entry as path.
+func (s *ListStore) ValidateAll(paramStore *param.Store, canvasStore *canvas.Store) (errs []error) {
+	for idx, entry := range *s {
+		if err := entry.isValid(paramStore, canvasStore); err != nil {
+			errs = append(errs, yaml.NewPathError(err, strconv.Itoa(idx)))
+		}
+	}
+	return errs
+}
+
 func (s *ListStore) deepCopy() (copy ListStore) {
 	copy = NewListStore()
 	for _, entry := range *s {
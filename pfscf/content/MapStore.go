@@ -4,6 +4,7 @@ import (
 	"fmt"
 
 	"github.com/Blesmol/pfscf/pfscf/canvas"
+	"github.com/Blesmol/pfscf/pfscf/color"
 	"github.com/Blesmol/pfscf/pfscf/param"
 	"github.com/Blesmol/pfscf/pfscf/preset"
 	"github.com/Blesmol/pfscf/pfscf/utils"
@@ -39,10 +40,10 @@ func (s *MapStore) InheritFrom(other MapStore) (err error) {
 	return nil
 }
 
-// Resolve resolves preset requirements for all entries in the ContentStore
-func (s *MapStore) Resolve(ps preset.Store) (err error) {
+// Resolve resolves preset requirements and named colors for all entries in the ContentStore
+func (s *MapStore) Resolve(ps preset.Store, cs color.Store) (err error) {
 	for _, entry := range *s {
-		if err := entry.resolve(ps); err != nil {
+		if err := entry.resolve(ps, cs); err != nil {
 			return err
 		}
 	}
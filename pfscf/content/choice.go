@@ -3,6 +3,7 @@ package content
 import (
 	"github.com/Blesmol/pfscf/pfscf/args"
 	"github.com/Blesmol/pfscf/pfscf/canvas"
+	"github.com/Blesmol/pfscf/pfscf/color"
 	"github.com/Blesmol/pfscf/pfscf/param"
 	"github.com/Blesmol/pfscf/pfscf/preset"
 	"github.com/Blesmol/pfscf/pfscf/stamp"
@@ -39,9 +40,9 @@ func (e *choice) isValid(paramStore *param.Store, canvasStore *canvas.Store) (er
 }
 
 // resolve the presets for this content object.
-func (e *choice) resolve(ps preset.Store) (err error) {
+func (e *choice) resolve(ps preset.Store, cs color.Store) (err error) {
 	for _, subStore := range e.Content {
-		if err = subStore.Resolve(ps); err != nil {
+		if err = subStore.Resolve(ps, cs); err != nil {
 			return err
 		}
 	}
@@ -1,17 +1,27 @@
 package content
 
 import (
-	"encoding/hex"
 	"fmt"
 	"regexp"
+	"strconv"
 	"strings"
 
 	"github.com/Blesmol/pfscf/pfscf/args"
+	"github.com/Blesmol/pfscf/pfscf/color"
+	"github.com/Blesmol/pfscf/pfscf/stamp"
 	"github.com/Blesmol/pfscf/pfscf/utils"
 )
 
+const (
+	dashSolid  = "solid"
+	dashDashed = "dashed"
+	dashDotted = "dotted"
+)
+
 var (
 	regexParamValue = regexp.MustCompile(`^\s*param:\s*(\S*)$`)
+
+	validDashes = []string{dashSolid, dashDashed, dashDotted}
 )
 
 // getValue returns the value that should be used for the current content.
@@ -60,35 +70,76 @@ func getMultiValue(contentValueField string, as *args.Store) (result []string) {
 	return []string{contentValueField}
 }
 
-func parseColor(color string) (r, g, b int, err error) {
-	regexHexColorCode := regexp.MustCompile(`^[0-9a-f]{6}$`)
-
-	color = strings.ToLower(strings.TrimSpace(color))
-
-	switch color {
-	case "white":
-		return 255, 255, 255, nil
-	case "black":
-		return 0, 0, 0, nil
-	case "blue":
-		return 0, 0, 255, nil
-	case "red":
-		return 255, 0, 0, nil
-	case "green":
-		return 0, 255, 0, nil
+// getTextOutputStyle returns the output style for text in the provided color.
+// If no color is provided, then black is used.
+func getTextOutputStyle(value string) (os stamp.OutputStyle, err error) {
+	if !utils.IsSet(value) {
+		return os, nil
 	}
 
-	colorCode := regexHexColorCode.FindString(color)
-	if utils.IsSet(colorCode) {
-		colorCodeBytes := []byte(colorCode)
-		decoded := make([]byte, hex.DecodedLen(len(colorCodeBytes)))
-		_, err := hex.Decode(decoded, colorCodeBytes)
-		utils.Assert(err == nil, fmt.Sprintf("Valid input should have been guaranteed by regexp, but instead got error: %v", err))
-		utils.Assert(len(decoded) == 3, fmt.Sprintf("Number of resultint entries should be guaranteed by regexp, was %v instead", len(decoded)))
+	c, err := color.Parse(value)
+	if err != nil {
+		return os, err
+	}
+
+	os.TextR, os.TextG, os.TextB = c.RGB()
+	os.Transparency = c.Transparency()
+	return os, nil
+}
+
+// getLineOutputStyle returns the output style for lines with the provided color, linewidth
+// and dash. See parseDash() for the supported dash values.
+func getLineOutputStyle(value string, linewidth float64, dash string) (os stamp.OutputStyle, err error) {
+	c, err := color.Parse(value)
+	if err != nil {
+		return os, err
+	}
+
+	os.DrawR, os.DrawG, os.DrawB = c.RGB()
+	os.Linewidth = linewidth
+	os.Transparency = c.Transparency()
+
+	if os.DashPattern, err = parseDash(dash, linewidth); err != nil {
+		return os, err
+	}
+	if dash == dashDotted {
+		os.LineCap = "round" // dashes of length zero are drawn as dots
+	}
+
+	return os, nil
+}
+
+// parseDash returns the dash pattern for the provided dash value. Supported are "solid",
+// "dashed" and "dotted", which scale with the linewidth, and custom patterns that consist of
+// alternating lengths of dashes and gaps in pt, e.g. "6 2 1 2". An empty value means solid.
+func parseDash(value string, linewidth float64) (pattern []float64, err error) {
+	switch value {
+	case "", dashSolid:
+		return nil, nil
+	case dashDashed:
+		return []float64{3.0 * linewidth, 2.0 * linewidth}, nil
+	case dashDotted:
+		return []float64{0.0, 2.0 * linewidth}, nil
+	}
+
+	fields := strings.FieldsFunc(value, func(r rune) bool { return r == ',' || r == ' ' })
+	if len(fields) == 0 {
+		return nil, fmt.Errorf("Unknown dash '%v'. Supported values are %v or a list of dash and gap lengths", value, validDashes)
+	}
+
+	var total float64
+	for _, field := range fields {
+		length, err := strconv.ParseFloat(field, 64)
+		if err != nil || length < 0.0 {
+			return nil, fmt.Errorf("Unknown dash '%v'. Supported values are %v or a list of dash and gap lengths", value, validDashes)
+		}
+		pattern = append(pattern, length)
+		total += length
+	}
 
-		r, g, b = int(decoded[0]), int(decoded[1]), int(decoded[2])
-		return r, g, b, nil
+	if total == 0.0 {
+		return nil, fmt.Errorf("Dash pattern '%v' must not consist of zero lengths only", value)
 	}
 
-	return 0, 0, 0, fmt.Errorf("Unknown color: '%v'", color)
+	return pattern, nil
 }
@@ -0,0 +1,107 @@
+package content
+
+import (
+	"testing"
+
+	test "github.com/Blesmol/pfscf/pfscf/testutils"
+)
+
+func TestGetLineOutputStyle(t *testing.T) {
+	t.Run("errors", func(t *testing.T) {
+		t.Run("unknown color", func(t *testing.T) {
+			_, err := getLineOutputStyle("foo", 1.0, "")
+			test.ExpectError(t, err, "Unknown color: 'foo'")
+		})
+
+		t.Run("unknown dash", func(t *testing.T) {
+			_, err := getLineOutputStyle("black", 1.0, "wavy")
+			test.ExpectError(t, err, "Unknown dash 'wavy'")
+		})
+	})
+
+	t.Run("valid", func(t *testing.T) {
+		t.Run("solid", func(t *testing.T) {
+			style, err := getLineOutputStyle("rgba(10, 20, 30, 0.5)", 2.5, "")
+			test.ExpectNoError(t, err)
+			test.ExpectEqual(t, style.DrawR, 10)
+			test.ExpectEqual(t, style.DrawG, 20)
+			test.ExpectEqual(t, style.DrawB, 30)
+			test.ExpectEqual(t, style.Linewidth, 2.5)
+			test.ExpectEqual(t, style.Transparency, 0.5)
+			test.ExpectEqual(t, len(style.DashPattern), 0)
+			test.ExpectNotSet(t, style.LineCap)
+		})
+
+		t.Run("dotted", func(t *testing.T) {
+			style, err := getLineOutputStyle("black", 2.0, dashDotted)
+			test.ExpectNoError(t, err)
+			test.ExpectEqual(t, len(style.DashPattern), 2)
+			test.ExpectEqual(t, style.DashPattern[0], 0.0)
+			test.ExpectEqual(t, style.DashPattern[1], 4.0)
+			test.ExpectEqual(t, style.LineCap, "round")
+		})
+	})
+}
+
+func TestParseDash(t *testing.T) {
+	t.Run("errors", func(t *testing.T) {
+		for _, value := range []string{"wavy", "1 x", "4 -2", ",", "1.0.0"} {
+			t.Logf("Testing value '%v'", value)
+			_, err := parseDash(value, 1.0)
+			test.ExpectError(t, err, "Unknown dash")
+		}
+
+		_, err := parseDash("0 0", 1.0)
+		test.ExpectError(t, err, "must not consist of zero lengths only")
+	})
+
+	t.Run("valid", func(t *testing.T) {
+		t.Run("solid", func(t *testing.T) {
+			for _, value := range []string{"", dashSolid} {
+				pattern, err := parseDash(value, 1.0)
+				test.ExpectNoError(t, err)
+				test.ExpectEqual(t, len(pattern), 0)
+			}
+		})
+
+		t.Run("dashed scales with linewidth", func(t *testing.T) {
+			pattern, err := parseDash(dashDashed, 2.0)
+			test.ExpectNoError(t, err)
+			test.ExpectEqual(t, len(pattern), 2)
+			test.ExpectEqual(t, pattern[0], 6.0)
+			test.ExpectEqual(t, pattern[1], 4.0)
+		})
+
+		t.Run("custom pattern", func(t *testing.T) {
+			pattern, err := parseDash("6 2,1.5, 2", 2.0)
+			test.ExpectNoError(t, err)
+			test.ExpectEqual(t, len(pattern), 4)
+			test.ExpectEqual(t, pattern[0], 6.0)
+			test.ExpectEqual(t, pattern[1], 2.0)
+			test.ExpectEqual(t, pattern[2], 1.5)
+			test.ExpectEqual(t, pattern[3], 2.0)
+		})
+	})
+}
+
+func TestGetTextOutputStyle(t *testing.T) {
+	t.Run("errors", func(t *testing.T) {
+		_, err := getTextOutputStyle("foo")
+		test.ExpectError(t, err, "Unknown color: 'foo'")
+	})
+
+	t.Run("valid", func(t *testing.T) {
+		style, err := getTextOutputStyle("")
+		test.ExpectNoError(t, err)
+		test.ExpectEqual(t, style.TextR, 0)
+		test.ExpectEqual(t, style.TextG, 0)
+		test.ExpectEqual(t, style.TextB, 0)
+
+		style, err = getTextOutputStyle("#0a141e")
+		test.ExpectNoError(t, err)
+		test.ExpectEqual(t, style.TextR, 10)
+		test.ExpectEqual(t, style.TextG, 20)
+		test.ExpectEqual(t, style.TextB, 30)
+		test.ExpectEqual(t, style.Transparency, 0.0)
+	})
+}
@@ -0,0 +1,172 @@
+package content
+
+import (
+	"fmt"
+
+	"github.com/Blesmol/pfscf/pfscf/args"
+	"github.com/Blesmol/pfscf/pfscf/canvas"
+	"github.com/Blesmol/pfscf/pfscf/color"
+	"github.com/Blesmol/pfscf/pfscf/param"
+	"github.com/Blesmol/pfscf/pfscf/preset"
+	"github.com/Blesmol/pfscf/pfscf/stamp"
+	"github.com/Blesmol/pfscf/pfscf/utils"
+)
+
+const (
+	typeEllipse = "ellipse"
+)
+
+var (
+	validEllipseStyles = []string{"outline", "filled"}
+)
+
+// ellipse draws an ellipse into an area, or a circle around a point, e.g. to circle
+// the chosen faction or boon.
+type ellipse struct {
+	X, Y         float64
+	X2, Y2       float64
+	Size         float64
+	Color        string
+	Linewidth    float64
+	Transparency float64
+	Style        string
+	Canvas       string
+	Presets      []string
+}
+
+func newEllipse() *ellipse {
+	var e ellipse
+	e.Presets = make([]string, 0)
+	return &e
+}
+
+func (e *ellipse) shouldDrawCentered() bool {
+	return utils.IsSet(e.Size) && utils.IsSet(e.X) && utils.IsSet(e.Y)
+}
+
+func (e *ellipse) shouldDrawArea() bool {
+	return utils.IsSet(e.X2) && utils.IsSet(e.Y2)
+}
+
+// isValid checks whether the current content object is valid and returns an
+// error with details if the object is not valid.
+func (e *ellipse) isValid(paramStore *param.Store, canvasStore *canvas.Store) (err error) {
+	err = utils.CheckFieldsAreSet(e, "Color", "Canvas", "Style")
+	if err != nil {
+		return contentValErr(e, err)
+	}
+
+	err = utils.CheckFieldsAreInRange(e, 0.0, 100.0, "X", "Y", "X2", "Y2", "Size", "Linewidth")
+	if err != nil {
+		return contentValErr(e, err)
+	}
+
+	if e.shouldDrawArea() && e.shouldDrawCentered() {
+		err := fmt.Errorf("Can only have either a 'size' value or 'x2'&'y2' values, but not both at the same time")
+		return contentValErr(e, err)
+	}
+
+	if _, exists := canvasStore.Get(e.Canvas); !exists {
+		err = fmt.Errorf("Canvas '%v' does not exist", e.Canvas)
+		return contentValErr(e, err)
+	}
+
+	if _, err = color.Parse(e.Color); err != nil {
+		return contentValErr(e, err)
+	}
+
+	if e.Transparency < 0.0 || e.Transparency > 1.0 {
+		err = fmt.Errorf("Transparency value outside of range 0.0 to 1.0: %v", e.Transparency)
+		return contentValErr(e, err)
+	}
+
+	if !utils.Contains(validEllipseStyles, e.Style) {
+		err = fmt.Errorf("Unknown style '%v'. Supported styles are %v", e.Style, validEllipseStyles)
+		return contentValErr(e, err)
+	}
+
+	return nil
+}
+
+// resolve the presets for this content object.
+func (e *ellipse) resolve(ps preset.Store, cs color.Store) (err error) {
+	// check that required presets are not contradicting each other
+	if err = ps.PresetsAreNotContradicting(e.Presets...); err != nil {
+		err = fmt.Errorf("Error resolving content: %v", err)
+		return
+	}
+
+	for _, presetID := range e.Presets {
+		preset, _ := ps.Get(presetID)
+		if err = preset.FillPublicFieldsFromPreset(e, "Presets"); err != nil {
+			err = fmt.Errorf("Error resolving content: %v", err)
+			return
+		}
+	}
+
+	// ensure coordinate sorting is correct
+	if e.shouldDrawArea() {
+		if e.X > e.X2 {
+			e.X, e.X2 = e.X2, e.X
+		}
+		if e.Y > e.Y2 {
+			e.Y, e.Y2 = e.Y2, e.Y
+		}
+	}
+
+	// defaults
+	if !utils.IsSet(e.Linewidth) {
+		e.Linewidth = 1.0
+	}
+	if !utils.IsSet(e.Style) {
+		e.Style = validEllipseStyles[0]
+	}
+
+	// named colors from the template
+	e.Color = cs.Resolve(e.Color)
+
+	return nil
+}
+
+// generateOutput generates the output for this content object.
+func (e *ellipse) generateOutput(s *stamp.Stamp, as *args.Store) (err error) {
+	if !e.shouldDrawArea() && !e.shouldDrawCentered() { // Nothing to do? No output! Also a way to have this disabled per default
+		return nil
+	}
+
+	c, err := color.Parse(e.Color)
+	if err != nil {
+		return err
+	}
+	r, g, b := c.RGB()
+
+	// the transparency of the entry is applied on top of the alpha value of the color
+	transparency := 1.0 - (1.0-e.Transparency)*c.A
+
+	var style stamp.OutputStyle
+	switch e.Style {
+	case "outline":
+		style = stamp.OutputStyle{Style: "D", DrawR: r, DrawG: g, DrawB: b, Linewidth: e.Linewidth, Transparency: transparency}
+	case "filled":
+		style = stamp.OutputStyle{Style: "F", FillR: r, FillG: g, FillB: b, Transparency: transparency}
+	default:
+		utils.Assert(false, "Should be unreachable, or some valid case is missing here")
+	}
+
+	switch {
+	case e.shouldDrawArea():
+		s.DrawEllipse(e.Canvas, e.X, e.Y, e.X2, e.Y2, style)
+	case e.shouldDrawCentered():
+		s.DrawCircle(e.Canvas, e.X, e.Y, e.Size, style)
+	}
+
+	return nil
+}
+
+// deepCopy creates a deep copy of this entry.
+func (e *ellipse) deepCopy() Entry {
+	copy := *e
+	copy.Presets = append(make([]string, 0), e.Presets...)
+
+	return &copy
+}
@@ -0,0 +1,179 @@
+package content
+
+import (
+	"testing"
+
+	"github.com/Blesmol/pfscf/pfscf/canvas"
+	"github.com/Blesmol/pfscf/pfscf/param"
+	"github.com/Blesmol/pfscf/pfscf/stamp"
+	test "github.com/Blesmol/pfscf/pfscf/testutils"
+)
+
+func getEllipseWithDummyData(presets ...string) (e *ellipse) {
+	e = newEllipse()
+
+	e.X = 12.0
+	e.Y = 12.0
+	e.X2 = 24.0
+	e.Y2 = 18.0
+	e.Color = "red"
+	e.Linewidth = 1.0
+	e.Style = "outline"
+	e.Canvas = "test"
+	e.Presets = append(e.Presets, presets...)
+
+	return e
+}
+
+func TestEllipse_IsValid(t *testing.T) {
+	paramStore := param.NewStore()
+	canvasStore := canvas.NewStore()
+	canvas := canvas.NewEntry()
+	testCoord := 10.0
+	canvas.X2 = &testCoord
+	canvas.Y2 = &testCoord
+	canvasStore.Add("test", &canvas)
+
+	t.Run("errors", func(t *testing.T) {
+		t.Run("missing color", func(t *testing.T) {
+			e := getEllipseWithDummyData()
+			e.Color = ""
+
+			err := e.isValid(&paramStore, &canvasStore)
+			test.ExpectError(t, err, "Missing value", "Color")
+		})
+
+		t.Run("size and area", func(t *testing.T) {
+			e := getEllipseWithDummyData()
+			e.Size = 5.0
+
+			err := e.isValid(&paramStore, &canvasStore)
+			test.ExpectError(t, err, "Can only have either a 'size' value or 'x2'&'y2' values")
+		})
+
+		t.Run("invalid canvas", func(t *testing.T) {
+			e := getEllipseWithDummyData()
+			e.Canvas = "foobar"
+
+			err := e.isValid(&paramStore, &canvasStore)
+			test.ExpectError(t, err, "Canvas 'foobar' does not exist")
+		})
+
+		t.Run("invalid transparency", func(t *testing.T) {
+			e := getEllipseWithDummyData()
+			e.Transparency = 1.5
+
+			err := e.isValid(&paramStore, &canvasStore)
+			test.ExpectError(t, err, "Transparency value outside of range")
+		})
+
+		t.Run("unknown style", func(t *testing.T) {
+			e := getEllipseWithDummyData()
+			e.Style = "strikeout"
+
+			err := e.isValid(&paramStore, &canvasStore)
+			test.ExpectError(t, err, "Unknown style 'strikeout'")
+		})
+	})
+
+	t.Run("valid", func(t *testing.T) {
+		t.Run("area", func(t *testing.T) {
+			e := getEllipseWithDummyData()
+
+			err := e.isValid(&paramStore, &canvasStore)
+			test.ExpectNoError(t, err)
+		})
+
+		t.Run("centered", func(t *testing.T) {
+			e := getEllipseWithDummyData()
+			e.X2, e.Y2 = 0.0, 0.0
+			e.Size = 5.0
+			e.Style = "filled"
+			e.Transparency = 0.5
+
+			err := e.isValid(&paramStore, &canvasStore)
+			test.ExpectNoError(t, err)
+		})
+	})
+}
+
+func TestEllipse_Resolve(t *testing.T) {
+	ps := getTestPresetStore(t)
+
+	t.Run("errors", func(t *testing.T) {
+		e := getEllipseWithDummyData("non-existing preset")
+
+		err := e.resolve(*ps, *getTestColorStore())
+		test.ExpectError(t, err, "does not exist")
+	})
+
+	t.Run("valid", func(t *testing.T) {
+		e := getEllipseWithDummyData("sameData1")
+		e.X = 0.0
+		e.Y = 30.0
+		e.Linewidth = 0.0
+		e.Style = ""
+
+		err := e.resolve(*ps, *getTestColorStore())
+		test.ExpectNoError(t, err)
+
+		test.ExpectEqual(t, e.X, 10.0)
+		test.ExpectEqual(t, e.Y, 18.0)
+		test.ExpectEqual(t, e.Y2, 30.0)
+		test.ExpectEqual(t, e.Linewidth, 1.0)
+		test.ExpectEqual(t, e.Style, "outline")
+	})
+}
+
+func TestEllipse_generateOutput(t *testing.T) {
+	newTestStamp := func() (s *stamp.Stamp) {
+		s = stamp.NewStamp(100.0, 100.0, 0.0, 0.0)
+		s.SetRecording(true)
+		s.AddCanvas("test", 0.0, 0.0, 100.0, 100.0)
+		return s
+	}
+	as := getTestArgStore("foo", "bar")
+
+	t.Run("area", func(t *testing.T) {
+		s := newTestStamp()
+		e := getEllipseWithDummyData()
+
+		test.ExpectNoError(t, e.generateOutput(s, as))
+		test.ExpectEqual(t, len(s.GetRecords()), 1)
+		test.ExpectEqual(t, s.GetRecords()[0].Type, "ellipse")
+	})
+
+	t.Run("centered", func(t *testing.T) {
+		s := newTestStamp()
+		e := getEllipseWithDummyData()
+		e.X2, e.Y2 = 0.0, 0.0
+		e.Size = 5.0
+		e.Style = "filled"
+
+		test.ExpectNoError(t, e.generateOutput(s, as))
+		test.ExpectEqual(t, len(s.GetRecords()), 1)
+		test.ExpectEqual(t, s.GetRecords()[0].Type, "circle")
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		s := newTestStamp()
+		e := getEllipseWithDummyData()
+		e.X2, e.Y2 = 0.0, 0.0
+
+		test.ExpectNoError(t, e.generateOutput(s, as))
+		test.ExpectEqual(t, len(s.GetRecords()), 0)
+	})
+}
+
+func TestEllipse_deepCopy(t *testing.T) {
+	e1 := newEllipse()
+	e1.X = 1.0
+	e1.Presets = append(e1.Presets, "t1")
+
+	e2 := e1.deepCopy().(*ellipse)
+	e2.X = 2.0
+	e2.Presets[0] = "t2"
+
+	test.ExpectNotEqual(t, e1.X, e2.X)
+	test.ExpectNotEqual(t, e1.Presets[0], e2.Presets[0])
+}
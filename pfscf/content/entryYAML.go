@@ -28,10 +28,18 @@ func (ey *entryYAML) UnmarshalYAML(unmarshal func(interface{}) error) (err error
 		ey.e = newStrikeout()
 	case typeChoice:
 		ey.e = newChoice()
+	case typeEllipse:
+		ey.e = newEllipse()
+	case typeImage:
+		ey.e = newImage()
 	case typeLine:
 		ey.e = newLine()
+	case typeMark:
+		ey.e = newMark()
 	case typeMultiline:
 		ey.e = newMultiline()
+	case typeParagraph:
+		ey.e = newParagraph()
 	case typeRectangle:
 		ey.e = newRectangle()
 	case typeText:
@@ -0,0 +1,138 @@
+package content
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/Blesmol/pfscf/pfscf/args"
+	"github.com/Blesmol/pfscf/pfscf/canvas"
+	"github.com/Blesmol/pfscf/pfscf/color"
+	"github.com/Blesmol/pfscf/pfscf/param"
+	"github.com/Blesmol/pfscf/pfscf/preset"
+	"github.com/Blesmol/pfscf/pfscf/stamp"
+	"github.com/Blesmol/pfscf/pfscf/utils"
+)
+
+const (
+	typeImage = "image"
+)
+
+var (
+	validImageFits = []string{stamp.ImageFitContain, stamp.ImageFitCover, stamp.ImageFitStretch}
+)
+
+// image places a PNG or JPEG file on the stamp, e.g. a signature or a logo.
+type image struct {
+	Value   string // filename of the image, or reference to a parameter containing it
+	X, Y    float64
+	X2, Y2  float64
+	Fit     string
+	Canvas  string
+	Presets []string
+
+	baseDir string // directory against which relative static filenames are resolved
+}
+
+func newImage() *image {
+	var e image
+	e.Presets = make([]string, 0)
+	return &e
+}
+
+// isValid checks whether the current content object is valid and returns an
+// error with details if the object is not valid.
+func (e *image) isValid(paramStore *param.Store, canvasStore *canvas.Store) (err error) {
+	err = utils.CheckFieldsAreSet(e, "Value", "Fit", "Canvas")
+	if err != nil {
+		return contentValErr(e, err)
+	}
+
+	err = utils.CheckFieldsAreInRange(e, 0.0, 100.0, "X", "Y", "X2", "Y2")
+	if err != nil {
+		return contentValErr(e, err)
+	}
+
+	if e.X == e.X2 {
+		err = fmt.Errorf("Coordinates for X axis are equal: %v", e.X)
+		return contentValErr(e, err)
+	}
+
+	if e.Y == e.Y2 {
+		err = fmt.Errorf("Coordinates for Y axis are equal: %v", e.Y)
+		return contentValErr(e, err)
+	}
+
+	if _, exists := canvasStore.Get(e.Canvas); !exists {
+		err = fmt.Errorf("Canvas '%v' does not exist", e.Canvas)
+		return contentValErr(e, err)
+	}
+
+	if !utils.Contains(validImageFits, e.Fit) {
+		err = fmt.Errorf("Unknown fit '%v'. Supported values are %v", e.Fit, validImageFits)
+		return contentValErr(e, err)
+	}
+
+	return nil
+}
+
+// resolve the presets for this content object.
+func (e *image) resolve(ps preset.Store, cs color.Store) (err error) {
+	// check that required presets are not contradicting each other
+	if err = ps.PresetsAreNotContradicting(e.Presets...); err != nil {
+		err = fmt.Errorf("Error resolving content: %v", err)
+		return
+	}
+
+	// apply presets
+	for _, presetID := range e.Presets {
+		preset, _ := ps.Get(presetID)
+		if err = preset.FillPublicFieldsFromPreset(e, "Presets"); err != nil {
+			err = fmt.Errorf("Error resolving content: %v", err)
+			return
+		}
+	}
+
+	// ensure coordinate sorting is correct
+	if e.X > e.X2 {
+		e.X, e.X2 = e.X2, e.X
+	}
+	if e.Y > e.Y2 {
+		e.Y, e.Y2 = e.Y2, e.Y
+	}
+
+	// defaults
+	if !utils.IsSet(e.Fit) {
+		e.Fit = stamp.ImageFitContain
+	}
+
+	return nil
+}
+
+// generateOutput generates the output for this object.
+func (e *image) generateOutput(s *stamp.Stamp, as *args.Store) (err error) {
+	filename := getValue(e.Value, as)
+	if filename == nil {
+		return nil // nothing to do here...
+	}
+
+	// static filenames are relative to the template file, filenames from parameters
+	// are relative to the current working directory
+	path := *filename
+	if !regexParamValue.MatchString(e.Value) && !filepath.IsAbs(path) {
+		path = filepath.Join(e.baseDir, path)
+	}
+
+	if err = s.AddImage(e.Canvas, e.X, e.Y, e.X2, e.Y2, path, e.Fit); err != nil {
+		return fmt.Errorf("Error generating content output: %v", err)
+	}
+
+	return nil
+}
+
+// deepCopy creates a deep copy of this entry.
+func (e *image) deepCopy() Entry {
+	copy := *e
+	copy.Presets = append(make([]string, 0), e.Presets...)
+
+	return &copy
+}
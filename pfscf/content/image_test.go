@@ -0,0 +1,185 @@
+package content
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/Blesmol/pfscf/pfscf/canvas"
+	"github.com/Blesmol/pfscf/pfscf/param"
+	"github.com/Blesmol/pfscf/pfscf/stamp"
+	test "github.com/Blesmol/pfscf/pfscf/testutils"
+	"github.com/Blesmol/pfscf/pfscf/utils"
+)
+
+var (
+	imageTestDir string
+)
+
+func init() {
+	utils.SetIsTestEnvironment(true)
+	imageTestDir = filepath.Join(utils.GetExecutableDir(), "testdata")
+}
+
+func getImageWithDummyData(presets ...string) (e *image) {
+	e = newImage()
+
+	e.Value = filepath.Join(imageTestDir, "signature.png")
+	e.X = 12.0
+	e.Y = 12.0
+	e.X2 = 24.0
+	e.Y2 = 24.0
+	e.Fit = stamp.ImageFitContain
+	e.Canvas = "test"
+	e.Presets = append(e.Presets, presets...)
+
+	return e
+}
+
+func TestImage_IsValid(t *testing.T) {
+	paramStore := param.NewStore()
+	canvasStore := canvas.NewStore()
+	canvas := canvas.NewEntry()
+	testCoord := 10.0
+	canvas.X2 = &testCoord
+	canvas.Y2 = &testCoord
+	canvasStore.Add("test", &canvas)
+
+	t.Run("errors", func(t *testing.T) {
+		t.Run("missing value", func(t *testing.T) {
+			e := getImageWithDummyData()
+			e.Value = ""
+
+			err := e.isValid(&paramStore, &canvasStore)
+			test.ExpectError(t, err, "Missing value", "Value")
+		})
+
+		t.Run("equal coordinates", func(t *testing.T) {
+			e := getImageWithDummyData()
+			e.X2 = e.X
+
+			err := e.isValid(&paramStore, &canvasStore)
+			test.ExpectError(t, err, "Coordinates for X axis are equal")
+		})
+
+		t.Run("invalid canvas", func(t *testing.T) {
+			e := getImageWithDummyData()
+			e.Canvas = "foobar"
+
+			err := e.isValid(&paramStore, &canvasStore)
+			test.ExpectError(t, err, "Canvas 'foobar' does not exist")
+		})
+
+		t.Run("invalid fit", func(t *testing.T) {
+			e := getImageWithDummyData()
+			e.Fit = "zoom"
+
+			err := e.isValid(&paramStore, &canvasStore)
+			test.ExpectError(t, err, "Unknown fit 'zoom'")
+		})
+	})
+
+	t.Run("valid", func(t *testing.T) {
+		e := getImageWithDummyData()
+		e.Value = "param:gm_signature"
+
+		err := e.isValid(&paramStore, &canvasStore)
+		test.ExpectNoError(t, err)
+	})
+}
+
+func TestImage_Resolve(t *testing.T) {
+	ps := getTestPresetStore(t)
+
+	t.Run("errors", func(t *testing.T) {
+		e := getImageWithDummyData("non-existing preset")
+
+		err := e.resolve(*ps, *getTestColorStore())
+		test.ExpectError(t, err, "does not exist")
+	})
+
+	t.Run("valid", func(t *testing.T) {
+		e := getImageWithDummyData("sameData1")
+		e.X = 0.0
+		e.Fit = ""
+
+		err := e.resolve(*ps, *getTestColorStore())
+		test.ExpectNoError(t, err)
+
+		test.ExpectEqual(t, e.X, 10.0)
+		test.ExpectEqual(t, e.Fit, stamp.ImageFitContain)
+	})
+}
+
+func TestImage_generateOutput(t *testing.T) {
+	stamp := stamp.NewStamp(100.0, 100.0, 0.0, 0.0)
+	stamp.AddCanvas("test", 0.0, 0.0, 100.0, 100.0)
+
+	t.Run("errors", func(t *testing.T) {
+		e := getImageWithDummyData()
+		e.Value = "param:gm_signature"
+		as := getTestArgStore("gm_signature", filepath.Join(imageTestDir, "nonExistant.png"))
+
+		err := e.generateOutput(stamp, as)
+		test.ExpectError(t, err, "Error generating content output", "Error reading image file")
+	})
+
+	t.Run("relative filename from parameter", func(t *testing.T) {
+		e := getImageWithDummyData()
+		e.Value = "param:gm_signature"
+		e.baseDir = imageTestDir
+		as := getTestArgStore("gm_signature", "signature.png")
+
+		err := e.generateOutput(stamp, as)
+		test.ExpectError(t, err, "Error reading image file")
+	})
+
+	t.Run("valid", func(t *testing.T) {
+		t.Run("static filename", func(t *testing.T) {
+			e := getImageWithDummyData()
+
+			err := e.generateOutput(stamp, getTestArgStore("foo", "bar"))
+			test.ExpectNoError(t, err)
+		})
+
+		t.Run("static filename relative to template", func(t *testing.T) {
+			e := getImageWithDummyData()
+			e.Value = "signature.png"
+			ls := NewListStore()
+			ls.add(e)
+			ls.SetBaseDir(imageTestDir)
+
+			err := e.generateOutput(stamp, getTestArgStore("foo", "bar"))
+			test.ExpectNoError(t, err)
+		})
+
+		t.Run("filename from parameter", func(t *testing.T) {
+			e := getImageWithDummyData()
+			e.Value = "param:gm_signature"
+			as := getTestArgStore("gm_signature", filepath.Join(imageTestDir, "signature.png"))
+
+			err := e.generateOutput(stamp, as)
+			test.ExpectNoError(t, err)
+		})
+
+		t.Run("parameter not provided", func(t *testing.T) {
+			e := getImageWithDummyData()
+			e.Value = "param:gm_signature"
+
+			err := e.generateOutput(stamp, getTestArgStore("foo", "bar"))
+			test.ExpectNoError(t, err)
+		})
+	})
+}
+
+func TestImage_deepCopy(t *testing.T) {
+	e1 := newImage()
+	e1.Value = "t1"
+	e1.Presets = append(e1.Presets, "t1")
+
+	e2 := e1.deepCopy().(*image)
+	e2.Value = "t2"
+	e2.Presets[0] = "t2"
+
+	test.ExpectNotEqual(t, e1.Value, e2.Value)
+	test.ExpectNotEqual(t, e1.Presets[0], e2.Presets[0])
+}
@@ -5,6 +5,7 @@ import (
 
 	"github.com/Blesmol/pfscf/pfscf/args"
 	"github.com/Blesmol/pfscf/pfscf/canvas"
+	"github.com/Blesmol/pfscf/pfscf/color"
 	"github.com/Blesmol/pfscf/pfscf/param"
 	"github.com/Blesmol/pfscf/pfscf/preset"
 	"github.com/Blesmol/pfscf/pfscf/stamp"
@@ -20,6 +21,7 @@ type line struct {
 	X2, Y2  float64
 	Linewidth float64
 	Color   string
+	Dash    string
 	Canvas  string
 	Presets []string
 }
@@ -48,7 +50,11 @@ func (e *line) isValid(paramStore *param.Store, canvasStore *canvas.Store) (err
 		return contentValErr(e, err)
 	}
 
-	if _, _, _, err = parseColor(e.Color); err != nil {
+	if _, err = color.Parse(e.Color); err != nil {
+		return contentValErr(e, err)
+	}
+
+	if _, err = parseDash(e.Dash, e.Linewidth); err != nil {
 		return contentValErr(e, err)
 	}
 
@@ -56,7 +62,7 @@ func (e *line) isValid(paramStore *param.Store, canvasStore *canvas.Store) (err
 }
 
 // resolve the presets for this content object.
-func (e *line) resolve(ps preset.Store) (err error) {
+func (e *line) resolve(ps preset.Store, cs color.Store) (err error) {
 	// check that required presets are not contradicting each other
 	if err = ps.PresetsAreNotContradicting(e.Presets...); err != nil {
 		err = fmt.Errorf("Error resolving content: %v", err)
@@ -76,6 +82,9 @@ func (e *line) resolve(ps preset.Store) (err error) {
 		e.Color = "black"
 	}
 
+	// named colors from the template
+	e.Color = cs.Resolve(e.Color)
+
 	return nil
 }
 
@@ -88,12 +97,11 @@ func (e *line) generateOutput(s *stamp.Stamp, as *args.Store) (err error) {
 		return nil
 	}
 
-	r, g, b, err := parseColor(e.Color)
+	style, err := getLineOutputStyle(e.Color, e.Linewidth, e.Dash)
 	if err != nil {
 		return err
 	}
 
-	style := stamp.OutputStyle{DrawR: r, DrawG: g, DrawB: b, Linewidth: e.Linewidth}
 	s.DrawLine(e.Canvas, e.X, e.Y, e.X2, e.Y2, style)
 
 	return nil
@@ -0,0 +1,124 @@
+package content
+
+import (
+	"fmt"
+
+	"github.com/Blesmol/pfscf/pfscf/args"
+	"github.com/Blesmol/pfscf/pfscf/canvas"
+	"github.com/Blesmol/pfscf/pfscf/color"
+	"github.com/Blesmol/pfscf/pfscf/param"
+	"github.com/Blesmol/pfscf/pfscf/preset"
+	"github.com/Blesmol/pfscf/pfscf/stamp"
+	"github.com/Blesmol/pfscf/pfscf/utils"
+)
+
+const (
+	typeMark = "mark"
+)
+
+var (
+	validMarkGlyphs = []string{stamp.MarkCross, stamp.MarkCheck, stamp.MarkCircle, stamp.MarkSquare}
+)
+
+// mark draws a single glyph centered on a point, e.g. to tick a checkbox.
+type mark struct {
+	X, Y      float64
+	Size      float64
+	Glyph     string
+	Linewidth float64
+	Color     string
+	Canvas    string
+	Presets   []string
+}
+
+func newMark() *mark {
+	var e mark
+	e.Presets = make([]string, 0)
+	return &e
+}
+
+// isValid checks whether the current content object is valid and returns an
+// error with details if the object is not valid.
+func (e *mark) isValid(paramStore *param.Store, canvasStore *canvas.Store) (err error) {
+	err = utils.CheckFieldsAreSet(e, "Size", "Glyph", "Canvas")
+	if err != nil {
+		return contentValErr(e, err)
+	}
+
+	err = utils.CheckFieldsAreInRange(e, 0.0, 100.0, "X", "Y", "Size", "Linewidth")
+	if err != nil {
+		return contentValErr(e, err)
+	}
+
+	if !utils.Contains(validMarkGlyphs, e.Glyph) {
+		err = fmt.Errorf("Unknown glyph '%v'. Supported values are %v", e.Glyph, validMarkGlyphs)
+		return contentValErr(e, err)
+	}
+
+	if _, err = color.Parse(e.Color); err != nil {
+		return contentValErr(e, err)
+	}
+
+	if _, exists := canvasStore.Get(e.Canvas); !exists {
+		err = fmt.Errorf("Canvas '%v' does not exist", e.Canvas)
+		return contentValErr(e, err)
+	}
+
+	return nil
+}
+
+// resolve the presets for this content object.
+func (e *mark) resolve(ps preset.Store, cs color.Store) (err error) {
+	// check that required presets are not contradicting each other
+	if err = ps.PresetsAreNotContradicting(e.Presets...); err != nil {
+		err = fmt.Errorf("Error resolving content: %v", err)
+		return
+	}
+
+	for _, presetID := range e.Presets {
+		preset, _ := ps.Get(presetID)
+		if err = preset.FillPublicFieldsFromPreset(e, "Presets"); err != nil {
+			err = fmt.Errorf("Error resolving content: %v", err)
+			return
+		}
+	}
+
+	// set defaults
+	if !utils.IsSet(e.Glyph) {
+		e.Glyph = stamp.MarkCross
+	}
+	if !utils.IsSet(e.Linewidth) {
+		e.Linewidth = 1.0
+	}
+	if !utils.IsSet(e.Color) {
+		e.Color = "black"
+	}
+
+	// named colors from the template
+	e.Color = cs.Resolve(e.Color)
+
+	return nil
+}
+
+// generateOutput generates the output for this content object.
+func (e *mark) generateOutput(s *stamp.Stamp, as *args.Store) (err error) {
+	style, err := getLineOutputStyle(e.Color, e.Linewidth, dashSolid)
+	if err != nil {
+		return err
+	}
+	style.FillR, style.FillG, style.FillB = style.DrawR, style.DrawG, style.DrawB
+
+	if err = s.DrawMark(e.Canvas, e.X, e.Y, e.Size, e.Glyph, style); err != nil {
+		return fmt.Errorf("Error generating content output: %v", err)
+	}
+
+	return nil
+}
+
+// deepCopy creates a deep copy of this entry.
+func (e *mark) deepCopy() Entry {
+	copy := *e
+	copy.Presets = append(make([]string, 0), e.Presets...)
+
+	return &copy
+}
@@ -0,0 +1,191 @@
+package content
+
+import (
+	"testing"
+
+	"github.com/Blesmol/pfscf/pfscf/canvas"
+	"github.com/Blesmol/pfscf/pfscf/param"
+	"github.com/Blesmol/pfscf/pfscf/stamp"
+	test "github.com/Blesmol/pfscf/pfscf/testutils"
+	"gopkg.in/yaml.v2"
+)
+
+func getMarkWithDummyData(presets ...string) (e *mark) {
+	e = newMark()
+
+	e.X = 12.0
+	e.Y = 12.0
+	e.Size = 2.0
+	e.Glyph = stamp.MarkCheck
+	e.Linewidth = 1.0
+	e.Color = "blue"
+	e.Canvas = "test"
+	e.Presets = append(e.Presets, presets...)
+
+	return e
+}
+
+func TestMark_IsValid(t *testing.T) {
+	paramStore := param.NewStore()
+	canvasStore := canvas.NewStore()
+	canvas := canvas.NewEntry()
+	testCoord := 10.0
+	canvas.X2 = &testCoord
+	canvas.Y2 = &testCoord
+	canvasStore.Add("test", &canvas)
+
+	t.Run("errors", func(t *testing.T) {
+		t.Run("missing size", func(t *testing.T) {
+			e := getMarkWithDummyData()
+			e.Size = 0.0
+
+			err := e.isValid(&paramStore, &canvasStore)
+			test.ExpectError(t, err, "Missing value", "Size")
+		})
+
+		t.Run("coordinate out of range", func(t *testing.T) {
+			e := getMarkWithDummyData()
+			e.X = 101.0
+
+			err := e.isValid(&paramStore, &canvasStore)
+			test.ExpectError(t, err, "X")
+		})
+
+		t.Run("unknown glyph", func(t *testing.T) {
+			e := getMarkWithDummyData()
+			e.Glyph = "star"
+
+			err := e.isValid(&paramStore, &canvasStore)
+			test.ExpectError(t, err, "Unknown glyph 'star'")
+		})
+
+		t.Run("unknown color", func(t *testing.T) {
+			e := getMarkWithDummyData()
+			e.Color = "mauve"
+
+			err := e.isValid(&paramStore, &canvasStore)
+			test.ExpectError(t, err, "Unknown color")
+		})
+
+		t.Run("invalid canvas", func(t *testing.T) {
+			e := getMarkWithDummyData()
+			e.Canvas = "foobar"
+
+			err := e.isValid(&paramStore, &canvasStore)
+			test.ExpectError(t, err, "Canvas 'foobar' does not exist")
+		})
+	})
+
+	t.Run("valid", func(t *testing.T) {
+		for _, glyph := range validMarkGlyphs {
+			e := getMarkWithDummyData()
+			e.Glyph = glyph
+
+			err := e.isValid(&paramStore, &canvasStore)
+			test.ExpectNoError(t, err)
+		}
+	})
+}
+
+func TestMark_Resolve(t *testing.T) {
+	ps := getTestPresetStore(t)
+
+	t.Run("errors", func(t *testing.T) {
+		e := getMarkWithDummyData("non-existing preset")
+
+		err := e.resolve(*ps, *getTestColorStore())
+		test.ExpectError(t, err, "does not exist")
+	})
+
+	t.Run("valid", func(t *testing.T) {
+		e := getMarkWithDummyData("sameData1")
+		e.X = 0.0
+		e.Glyph = ""
+		e.Linewidth = 0.0
+		e.Color = ""
+
+		err := e.resolve(*ps, *getTestColorStore())
+		test.ExpectNoError(t, err)
+
+		test.ExpectEqual(t, e.X, 10.0)
+		test.ExpectEqual(t, e.Glyph, stamp.MarkCross)
+		test.ExpectEqual(t, e.Linewidth, 1.0)
+		test.ExpectEqual(t, e.Color, "black")
+	})
+}
+
+func TestMark_generateOutput(t *testing.T) {
+	s := stamp.NewStamp(100.0, 100.0, 0.0, 0.0)
+	s.SetRecording(true)
+	s.AddCanvas("test", 0.0, 0.0, 100.0, 100.0)
+
+	t.Run("errors", func(t *testing.T) {
+		e := getMarkWithDummyData()
+		e.Glyph = "star"
+
+		err := e.generateOutput(s, getTestArgStore("foo", "bar"))
+		test.ExpectError(t, err, "Error generating content output", "Unknown glyph 'star'")
+	})
+
+	t.Run("valid", func(t *testing.T) {
+		e := getMarkWithDummyData()
+
+		err := e.generateOutput(s, getTestArgStore("foo", "bar"))
+		test.ExpectNoError(t, err)
+
+		records := s.GetRecords()
+		test.ExpectEqual(t, len(records), 1)
+		test.ExpectEqual(t, records[0].Type, "mark")
+		test.ExpectEqual(t, records[0].Value, stamp.MarkCheck)
+	})
+
+	t.Run("as part of a choice", func(t *testing.T) {
+		choiceYAML := `
+- type: choice
+  choices: param:faction
+  content:
+    Envoy's Alliance:
+      - type: mark
+        x: 10.0
+        y: 20.0
+        size: 3.0
+        glyph: check
+        canvas: test
+    Grand Archive:
+      - type: mark
+        x: 10.0
+        y: 30.0
+        size: 3.0
+        glyph: square
+        canvas: test
+`
+		var ls ListStore
+		err := yaml.Unmarshal([]byte(choiceYAML), &ls)
+		test.ExpectNoError(t, err)
+		test.ExpectNoError(t, ls.Resolve(*getTestPresetStore(t), *getTestColorStore()))
+
+		s := stamp.NewStamp(100.0, 100.0, 0.0, 0.0)
+		s.SetRecording(true)
+		s.AddCanvas("test", 0.0, 0.0, 100.0, 100.0)
+
+		err = ls.GenerateOutput(s, getTestArgStore("faction", "Grand Archive"))
+		test.ExpectNoError(t, err)
+
+		records := s.GetRecords()
+		test.ExpectEqual(t, len(records), 1)
+		test.ExpectEqual(t, records[0].Value, stamp.MarkSquare)
+	})
+}
+
+func TestMark_deepCopy(t *testing.T) {
+	e1 := newMark()
+	e1.Glyph = "t1"
+	e1.Presets = append(e1.Presets, "t1")
+
+	e2 := e1.deepCopy().(*mark)
+	e2.Glyph = "t2"
+	e2.Presets[0] = "t2"
+
+	test.ExpectNotEqual(t, e1.Glyph, e2.Glyph)
+	test.ExpectNotEqual(t, e1.Presets[0], e2.Presets[0])
+}
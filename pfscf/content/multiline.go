@@ -5,6 +5,7 @@ import (
 
 	"github.com/Blesmol/pfscf/pfscf/args"
 	"github.com/Blesmol/pfscf/pfscf/canvas"
+	"github.com/Blesmol/pfscf/pfscf/color"
 	"github.com/Blesmol/pfscf/pfscf/param"
 	"github.com/Blesmol/pfscf/pfscf/preset"
 	"github.com/Blesmol/pfscf/pfscf/stamp"
@@ -16,15 +17,17 @@ const (
 )
 
 type multiline struct {
-	Value    string
-	X, Y     float64
-	X2, Y2   float64
-	Lines    int
-	Font     string
-	Fontsize float64
-	Align    string
-	Canvas   string
-	Presets  []string
+	Value     string
+	X, Y      float64
+	X2, Y2    float64
+	Lines     int
+	Font      string
+	Fontstyle string
+	Fontsize  float64
+	Color     string
+	Align     string
+	Canvas    string
+	Presets   []string
 }
 
 func newMultiline() *multiline {
@@ -61,11 +64,25 @@ func (e *multiline) isValid(paramStore *param.Store, canvasStore *canvas.Store)
 		return contentValErr(e, err)
 	}
 
+	if err = stamp.CheckFont(e.Font, e.Fontstyle); err != nil {
+		return contentValErr(e, err)
+	}
+
+	if err = stamp.CheckAlign(e.Align); err != nil {
+		return contentValErr(e, err)
+	}
+
+	if utils.IsSet(e.Color) {
+		if _, err = color.Parse(e.Color); err != nil {
+			return contentValErr(e, err)
+		}
+	}
+
 	return nil
 }
 
 // resolve the presets for this content object.
-func (e *multiline) resolve(ps preset.Store) (err error) {
+func (e *multiline) resolve(ps preset.Store, cs color.Store) (err error) {
 	// check that required presets are not contradicting each other
 	if err = ps.PresetsAreNotContradicting(e.Presets...); err != nil {
 		err = fmt.Errorf("Error resolving content: %v", err)
@@ -89,6 +106,9 @@ func (e *multiline) resolve(ps preset.Store) (err error) {
 		e.Y, e.Y2 = e.Y2, e.Y
 	}
 
+	// named colors from the template
+	e.Color = cs.Resolve(e.Color)
+
 	return nil
 }
 
@@ -103,13 +123,18 @@ func (e *multiline) generateOutput(s *stamp.Stamp, as *args.Store) (err error) {
 		return fmt.Errorf("Error generating content output: Current multiline content has a maxium of %v lines, but %v input lines were provided", e.Lines, len(valueArray))
 	}
 
+	style, err := getTextOutputStyle(e.Color)
+	if err != nil {
+		return err
+	}
+
 	for idx, text := range valueArray {
 		if !utils.IsSet(text) {
 			continue
 		}
 
 		x, y, x2, y2 := e.getLineCoords(idx+1)
-		s.AddTextCell(e.Canvas, x, y, x2, y2, e.Font, e.Fontsize, e.Align, text, true)
+		s.AddTextCell(e.Canvas, x, y, x2, y2, e.Font, e.Fontstyle, e.Fontsize, e.Align, 0.0, text, true, style)
 	}
 
 	return nil
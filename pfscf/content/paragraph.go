@@ -0,0 +1,183 @@
+package content
+
+import (
+	"fmt"
+
+	"github.com/Blesmol/pfscf/pfscf/args"
+	"github.com/Blesmol/pfscf/pfscf/canvas"
+	"github.com/Blesmol/pfscf/pfscf/color"
+	"github.com/Blesmol/pfscf/pfscf/param"
+	"github.com/Blesmol/pfscf/pfscf/preset"
+	"github.com/Blesmol/pfscf/pfscf/stamp"
+	"github.com/Blesmol/pfscf/pfscf/utils"
+)
+
+const (
+	typeParagraph = "paragraph"
+
+	overflowEllipsis = "ellipsis"
+	overflowError    = "error"
+)
+
+var (
+	validOverflows       = []string{overflowEllipsis, overflowError}
+	validParagraphAligns = []string{"L", "C", "R"}
+)
+
+// paragraph is a text box where a single value is wrapped into multiple lines.
+type paragraph struct {
+	Value      string
+	X, Y       float64
+	X2, Y2     float64
+	Font       string
+	Fontstyle  string
+	Fontsize   float64
+	Lineheight float64 // factor of the fontsize
+	Lines      int     // maximum number of lines, 0 if only limited by the height
+	Overflow   string
+	Color      string
+	Align      string
+	Canvas     string
+	Presets    []string
+}
+
+func newParagraph() *paragraph {
+	var e paragraph
+	e.Presets = make([]string, 0)
+	return &e
+}
+
+// isValid checks whether the current content object is valid and returns an
+// error with details if the object is not valid.
+func (e *paragraph) isValid(paramStore *param.Store, canvasStore *canvas.Store) (err error) {
+	err = utils.CheckFieldsAreSet(e, "Value", "Font", "Fontsize", "Lineheight", "Overflow", "Align", "Canvas")
+	if err != nil {
+		return contentValErr(e, err)
+	}
+
+	err = utils.CheckFieldsAreInRange(e, 0.0, 100.0, "X", "Y", "X2", "Y2")
+	if err != nil {
+		return contentValErr(e, err)
+	}
+
+	if e.X == e.X2 {
+		err = fmt.Errorf("Coordinates for X axis are equal: %v", e.X)
+		return contentValErr(e, err)
+	}
+
+	if e.Y == e.Y2 {
+		err = fmt.Errorf("Coordinates for Y axis are equal: %v", e.Y)
+		return contentValErr(e, err)
+	}
+
+	if _, exists := canvasStore.Get(e.Canvas); !exists {
+		err = fmt.Errorf("Canvas '%v' does not exist", e.Canvas)
+		return contentValErr(e, err)
+	}
+
+	if err = stamp.CheckFont(e.Font, e.Fontstyle); err != nil {
+		return contentValErr(e, err)
+	}
+
+	if utils.IsSet(e.Color) {
+		if _, err = color.Parse(e.Color); err != nil {
+			return contentValErr(e, err)
+		}
+	}
+
+	if err = stamp.CheckParagraphFontsize(e.Fontsize); err != nil {
+		return contentValErr(e, err)
+	}
+
+	if e.Lineheight < 0.0 {
+		err = fmt.Errorf("Lineheight must not be negative: %v", e.Lineheight)
+		return contentValErr(e, err)
+	}
+
+	if e.Lines < 0 {
+		err = fmt.Errorf("Number of lines must not be negative: %v", e.Lines)
+		return contentValErr(e, err)
+	}
+
+	if !utils.Contains(validOverflows, e.Overflow) {
+		err = fmt.Errorf("Unknown overflow '%v'. Supported values are %v", e.Overflow, validOverflows)
+		return contentValErr(e, err)
+	}
+
+	if !utils.Contains(validParagraphAligns, e.Align) {
+		err = fmt.Errorf("Unknown alignment '%v'. Supported alignments are %v", e.Align, validParagraphAligns)
+		return contentValErr(e, err)
+	}
+
+	return nil
+}
+
+// resolve the presets for this content object.
+func (e *paragraph) resolve(ps preset.Store, cs color.Store) (err error) {
+	// check that required presets are not contradicting each other
+	if err = ps.PresetsAreNotContradicting(e.Presets...); err != nil {
+		err = fmt.Errorf("Error resolving content: %v", err)
+		return
+	}
+
+	// apply presets
+	for _, presetID := range e.Presets {
+		preset, _ := ps.Get(presetID)
+		if err = preset.FillPublicFieldsFromPreset(e, "Presets"); err != nil {
+			err = fmt.Errorf("Error resolving content: %v", err)
+			return
+		}
+	}
+
+	// ensure coordinate sorting is correct
+	if e.X > e.X2 {
+		e.X, e.X2 = e.X2, e.X
+	}
+	if e.Y > e.Y2 {
+		e.Y, e.Y2 = e.Y2, e.Y
+	}
+
+	// defaults
+	if e.Lineheight == 0.0 {
+		e.Lineheight = 1.2
+	}
+	if !utils.IsSet(e.Overflow) {
+		e.Overflow = overflowEllipsis
+	}
+	if !utils.IsSet(e.Align) {
+		e.Align = "L"
+	}
+
+	// named colors from the template
+	e.Color = cs.Resolve(e.Color)
+
+	return nil
+}
+
+// generateOutput generates the output for this object.
+func (e *paragraph) generateOutput(s *stamp.Stamp, as *args.Store) (err error) {
+	value := getValue(e.Value, as)
+	if value == nil {
+		return nil // nothing to do here...
+	}
+
+	style, err := getTextOutputStyle(e.Color)
+	if err != nil {
+		return err
+	}
+
+	err = s.AddParagraphCell(e.Canvas, e.X, e.Y, e.X2, e.Y2, e.Font, e.Fontstyle, e.Fontsize, e.Lineheight, e.Lines, e.Align, *value, e.Overflow == overflowEllipsis, style)
+	if err != nil {
+		return fmt.Errorf("Error generating content output: %v", err)
+	}
+
+	return nil
+}
+
+// deepCopy creates a deep copy of this entry.
+func (e *paragraph) deepCopy() Entry {
+	copy := *e
+	copy.Presets = append(make([]string, 0), e.Presets...)
+
+	return &copy
+}
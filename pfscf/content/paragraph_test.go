@@ -0,0 +1,161 @@
+package content
+
+import (
+	"testing"
+
+	"github.com/Blesmol/pfscf/pfscf/canvas"
+	"github.com/Blesmol/pfscf/pfscf/param"
+	"github.com/Blesmol/pfscf/pfscf/stamp"
+	test "github.com/Blesmol/pfscf/pfscf/testutils"
+)
+
+func getParagraphWithDummyData(presets ...string) (e *paragraph) {
+	e = newParagraph()
+
+	e.Value = "Some value"
+	e.X = 12.0
+	e.Y = 12.0
+	e.X2 = 24.0
+	e.Y2 = 24.0
+	e.Font = "Helvetica"
+	e.Fontsize = 14.0
+	e.Lineheight = 1.2
+	e.Overflow = overflowEllipsis
+	e.Align = "L"
+	e.Canvas = "test"
+	e.Presets = append(e.Presets, presets...)
+
+	return e
+}
+
+func TestParagraph_IsValid(t *testing.T) {
+	paramStore := param.NewStore()
+	canvasStore := canvas.NewStore()
+	canvas := canvas.NewEntry()
+	testCoord := 10.0
+	canvas.X2 = &testCoord
+	canvas.Y2 = &testCoord
+	canvasStore.Add("test", &canvas)
+
+	t.Run("errors", func(t *testing.T) {
+		t.Run("missing value", func(t *testing.T) {
+			e := getParagraphWithDummyData()
+			e.Font = ""
+
+			err := e.isValid(&paramStore, &canvasStore)
+			test.ExpectError(t, err, "Missing value", "Font")
+		})
+
+		t.Run("equal coordinates", func(t *testing.T) {
+			e := getParagraphWithDummyData()
+			e.Y2 = e.Y
+
+			err := e.isValid(&paramStore, &canvasStore)
+			test.ExpectError(t, err, "Coordinates for Y axis are equal")
+		})
+
+		t.Run("fontsize too small", func(t *testing.T) {
+			e := getParagraphWithDummyData()
+			e.Fontsize = 3.0
+
+			err := e.isValid(&paramStore, &canvasStore)
+			test.ExpectError(t, err, "Fontsize 3 is smaller than the minimum font size 4")
+		})
+
+		t.Run("negative number of lines", func(t *testing.T) {
+			e := getParagraphWithDummyData()
+			e.Lines = -1
+
+			err := e.isValid(&paramStore, &canvasStore)
+			test.ExpectError(t, err, "Number of lines must not be negative")
+		})
+
+		t.Run("invalid overflow", func(t *testing.T) {
+			e := getParagraphWithDummyData()
+			e.Overflow = "hide"
+
+			err := e.isValid(&paramStore, &canvasStore)
+			test.ExpectError(t, err, "Unknown overflow 'hide'")
+		})
+
+		t.Run("invalid alignment", func(t *testing.T) {
+			e := getParagraphWithDummyData()
+			e.Align = "CB"
+
+			err := e.isValid(&paramStore, &canvasStore)
+			test.ExpectError(t, err, "Unknown alignment 'CB'")
+		})
+	})
+
+	t.Run("valid", func(t *testing.T) {
+		e := getParagraphWithDummyData()
+		e.Lines = 3
+		e.Color = "red"
+
+		err := e.isValid(&paramStore, &canvasStore)
+		test.ExpectNoError(t, err)
+	})
+}
+
+func TestParagraph_Resolve(t *testing.T) {
+	ps := getTestPresetStore(t)
+
+	t.Run("errors", func(t *testing.T) {
+		e := getParagraphWithDummyData("conflict1", "conflict2")
+
+		err := e.resolve(*ps, *getTestColorStore())
+		test.ExpectError(t, err, "Contradicting values", "font", "conflict1", "conflict2")
+	})
+
+	t.Run("valid", func(t *testing.T) {
+		e := newParagraph()
+		e.Presets = append(e.Presets, "sameData1")
+		e.Y, e.Y2 = 20.0, 10.0
+
+		err := e.resolve(*ps, *getTestColorStore())
+		test.ExpectNoError(t, err)
+
+		test.ExpectEqual(t, e.Font, "Helvetica")
+		test.ExpectEqual(t, e.Y, 10.0)
+		test.ExpectEqual(t, e.Y2, 20.0)
+		test.ExpectEqual(t, e.Lineheight, 1.2)
+		test.ExpectEqual(t, e.Overflow, overflowEllipsis)
+		test.ExpectEqual(t, e.Align, "L")
+	})
+}
+
+func TestParagraph_generateOutput(t *testing.T) {
+	stamp := stamp.NewStamp(100.0, 100.0, 0.0, 0.0)
+	stamp.AddCanvas("test", 0.0, 0.0, 100.0, 100.0)
+	as := getTestArgStore("someId", "Lorem ipsum dolor sit amet, consectetur adipiscing elit, sed do eiusmod tempor incididunt ut labore et dolore magna aliqua.")
+
+	t.Run("errors", func(t *testing.T) {
+		e := getParagraphWithDummyData()
+		e.Value = "param:someId"
+		e.Overflow = overflowError
+
+		err := e.generateOutput(stamp, as)
+		test.ExpectError(t, err, "Error generating content output", "does not fit")
+	})
+
+	t.Run("valid", func(t *testing.T) {
+		e := getParagraphWithDummyData()
+		e.Value = "param:someId"
+
+		err := e.generateOutput(stamp, as)
+		test.ExpectNoError(t, err)
+	})
+}
+
+func TestParagraph_deepCopy(t *testing.T) {
+	e1 := newParagraph()
+	e1.Value = "t1"
+	e1.Presets = append(e1.Presets, "t1")
+
+	e2 := e1.deepCopy().(*paragraph)
+	e2.Value = "t2"
+	e2.Presets[0] = "t2"
+
+	test.ExpectNotEqual(t, e1.Value, e2.Value)
+	test.ExpectNotEqual(t, e1.Presets[0], e2.Presets[0])
+}
@@ -5,6 +5,7 @@ import (
 
 	"github.com/Blesmol/pfscf/pfscf/args"
 	"github.com/Blesmol/pfscf/pfscf/canvas"
+	"github.com/Blesmol/pfscf/pfscf/color"
 	"github.com/Blesmol/pfscf/pfscf/param"
 	"github.com/Blesmol/pfscf/pfscf/preset"
 	"github.com/Blesmol/pfscf/pfscf/stamp"
@@ -26,6 +27,7 @@ type rectangle struct {
 	Color        string
 	Transparency float64 // TODO convert to ptr
 	Style        string
+	Dash         string // only used for style strikeout
 	Canvas       string
 	Presets      []string
 }
@@ -54,7 +56,7 @@ func (e *rectangle) isValid(paramStore *param.Store, canvasStore *canvas.Store)
 		return contentValErr(e, err)
 	}
 
-	if _, _, _, err = parseColor(e.Color); err != nil {
+	if _, err = color.Parse(e.Color); err != nil {
 		return contentValErr(e, err)
 	}
 
@@ -68,11 +70,15 @@ func (e *rectangle) isValid(paramStore *param.Store, canvasStore *canvas.Store)
 		return contentValErr(e, err)
 	}
 
+	if _, err = parseDash(e.Dash, 1.0); err != nil {
+		return contentValErr(e, err)
+	}
+
 	return nil
 }
 
 // resolve the presets for this content object.
-func (e *rectangle) resolve(ps preset.Store) (err error) {
+func (e *rectangle) resolve(ps preset.Store, cs color.Store) (err error) {
 	// check that required presets are not contradicting each other
 	if err = ps.PresetsAreNotContradicting(e.Presets...); err != nil {
 		err = fmt.Errorf("Error resolving content: %v", err)
@@ -95,6 +101,9 @@ func (e *rectangle) resolve(ps preset.Store) (err error) {
 		e.Style = validStyles[0]
 	}
 
+	// named colors from the template
+	e.Color = cs.Resolve(e.Color)
+
 	return nil
 }
 
@@ -105,17 +114,24 @@ func (e *rectangle) generateOutput(s *stamp.Stamp, as *args.Store) (err error) {
 		return nil
 	}
 
-	r, g, b, err := parseColor(e.Color)
+	c, err := color.Parse(e.Color)
 	if err != nil {
 		return err
 	}
+	r, g, b := c.RGB()
+
+	// the transparency of the entry is applied on top of the alpha value of the color
+	transparency := 1.0 - (1.0-e.Transparency)*c.A
 
 	switch e.Style {
 	case "filled":
-		style := stamp.OutputStyle{Style: "F", FillR: r, FillG: g, FillB: b, Transparency: e.Transparency}
+		style := stamp.OutputStyle{Style: "F", FillR: r, FillG: g, FillB: b, Transparency: transparency}
 		s.DrawRectangle(e.Canvas, e.X, e.Y, e.X2, e.Y2, style)
 	case "strikeout":
-		style := stamp.OutputStyle{DrawR: r, DrawB: b, DrawG: g, Linewidth: 2.5}
+		style, err := getLineOutputStyle(e.Color, 2.5, e.Dash)
+		if err != nil {
+			return err
+		}
 		s.DrawStrikeoutArea(e.Canvas, e.X, e.Y, e.X2, e.Y2, style)
 	default:
 		utils.Assert(false, "Should be unreachable, or some valid case is missing here")
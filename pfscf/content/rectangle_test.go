@@ -3,9 +3,63 @@ package content
 import (
 	"testing"
 
+	"github.com/Blesmol/pfscf/pfscf/canvas"
+	"github.com/Blesmol/pfscf/pfscf/param"
 	test "github.com/Blesmol/pfscf/pfscf/testutils"
 )
 
+func getRectangleWithDummyData(presets ...string) (e *rectangle) {
+	e = newRectangle()
+
+	e.X = 10.0
+	e.Y = 10.0
+	e.X2 = 20.0
+	e.Y2 = 20.0
+	e.Color = "blue"
+	e.Style = "strikeout"
+	e.Canvas = "test"
+	e.Presets = append(e.Presets, presets...)
+
+	return e
+}
+
+func TestRectangle_IsValid(t *testing.T) {
+	paramStore := param.NewStore()
+	canvasStore := canvas.NewStore()
+	canvas := canvas.NewEntry()
+	testCoord := 10.0
+	canvas.X2 = &testCoord
+	canvas.Y2 = &testCoord
+	canvasStore.Add("test", &canvas)
+
+	t.Run("errors", func(t *testing.T) {
+		t.Run("unknown style", func(t *testing.T) {
+			e := getRectangleWithDummyData()
+			e.Style = "outline"
+
+			err := e.isValid(&paramStore, &canvasStore)
+			test.ExpectError(t, err, "Unknown style 'outline'")
+		})
+
+		t.Run("unknown dash", func(t *testing.T) {
+			e := getRectangleWithDummyData()
+			e.Dash = "wavy"
+
+			err := e.isValid(&paramStore, &canvasStore)
+			test.ExpectError(t, err, "Unknown dash 'wavy'")
+		})
+	})
+
+	t.Run("valid", func(t *testing.T) {
+		for _, dash := range []string{"", dashSolid, dashDashed, dashDotted, "4 2"} {
+			e := getRectangleWithDummyData()
+			e.Dash = dash
+
+			test.ExpectNoError(t, e.isValid(&paramStore, &canvasStore))
+		}
+	})
+}
+
 func TestRectangle_deepCopy(t *testing.T) {
 	e1 := newRectangle()
 	e1.X = 1.0
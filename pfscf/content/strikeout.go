@@ -5,6 +5,7 @@ import (
 
 	"github.com/Blesmol/pfscf/pfscf/args"
 	"github.com/Blesmol/pfscf/pfscf/canvas"
+	"github.com/Blesmol/pfscf/pfscf/color"
 	"github.com/Blesmol/pfscf/pfscf/param"
 	"github.com/Blesmol/pfscf/pfscf/preset"
 	"github.com/Blesmol/pfscf/pfscf/stamp"
@@ -22,6 +23,7 @@ type strikeout struct {
 	Size      float64
 	Linewidth float64
 	Color     string
+	Dash      string
 	Canvas    string
 	Presets   []string
 }
@@ -53,7 +55,11 @@ func (e *strikeout) isValid(paramStore *param.Store, canvasStore *canvas.Store)
 		return contentValErr(e, err)
 	}
 
-	if _, _, _, err = parseColor(e.Color); err != nil {
+	if _, err = color.Parse(e.Color); err != nil {
+		return contentValErr(e, err)
+	}
+
+	if _, err = parseDash(e.Dash, e.Linewidth); err != nil {
 		return contentValErr(e, err)
 	}
 
@@ -71,7 +77,7 @@ func (e *strikeout) isValid(paramStore *param.Store, canvasStore *canvas.Store)
 }
 
 // resolve the presets for this content object.
-func (e *strikeout) resolve(ps preset.Store) (err error) {
+func (e *strikeout) resolve(ps preset.Store, cs color.Store) (err error) {
 	// check that required presets are not contradicting each other
 	if err = ps.PresetsAreNotContradicting(e.Presets...); err != nil {
 		err = fmt.Errorf("Error resolving content: %v", err)
@@ -94,6 +100,9 @@ func (e *strikeout) resolve(ps preset.Store) (err error) {
 		e.Color = "black"
 	}
 
+	// named colors from the template
+	e.Color = cs.Resolve(e.Color)
+
 	return nil
 }
 
@@ -103,11 +112,10 @@ func (e *strikeout) generateOutput(s *stamp.Stamp, as *args.Store) (err error) {
 		return nil
 	}
 
-	r, g, b, err := parseColor(e.Color)
+	style, err := getLineOutputStyle(e.Color, e.Linewidth, e.Dash)
 	if err != nil {
 		return err
 	}
-	style := stamp.OutputStyle{DrawR: r, DrawB: g, DrawG: b, Linewidth: e.Linewidth}
 
 	switch {
 	case e.shouldDrawArea():
@@ -5,6 +5,7 @@ import (
 
 	"github.com/Blesmol/pfscf/pfscf/args"
 	"github.com/Blesmol/pfscf/pfscf/canvas"
+	"github.com/Blesmol/pfscf/pfscf/color"
 	"github.com/Blesmol/pfscf/pfscf/param"
 	"github.com/Blesmol/pfscf/pfscf/preset"
 	"github.com/Blesmol/pfscf/pfscf/stamp"
@@ -18,14 +19,17 @@ const (
 // text is the final type to implement text cells.
 // TODO switch to pointers to distinguish between unset values and zero values?
 type text struct {
-	Value    string
-	X, Y     float64
-	X2, Y2   float64
-	Font     string
-	Fontsize float64
-	Align    string
-	Canvas   string
-	Presets  []string
+	Value     string
+	X, Y      float64
+	X2, Y2    float64
+	Font      string
+	Fontstyle string
+	Fontsize  float64
+	Color     string
+	Align     string
+	Rotation  float64 // counter-clockwise, in degrees
+	Canvas    string
+	Presets   []string
 }
 
 func newText() *text {
@@ -52,11 +56,30 @@ func (e *text) isValid(paramStore *param.Store, canvasStore *canvas.Store) (err
 		return contentValErr(e, err)
 	}
 
+	if err = stamp.CheckFont(e.Font, e.Fontstyle); err != nil {
+		return contentValErr(e, err)
+	}
+
+	if err = stamp.CheckAlign(e.Align); err != nil {
+		return contentValErr(e, err)
+	}
+
+	if e.Rotation != 0.0 && e.Y2 == 0.0 {
+		err = fmt.Errorf("Rotated text requires coordinate Y2")
+		return contentValErr(e, err)
+	}
+
+	if utils.IsSet(e.Color) {
+		if _, err = color.Parse(e.Color); err != nil {
+			return contentValErr(e, err)
+		}
+	}
+
 	return nil
 }
 
 // resolve the presets for this content object.
-func (e *text) resolve(ps preset.Store) (err error) {
+func (e *text) resolve(ps preset.Store, cs color.Store) (err error) {
 	// check that required presets are not contradicting each other
 	if err = ps.PresetsAreNotContradicting(e.Presets...); err != nil {
 		err = fmt.Errorf("Error resolving content: %v", err)
@@ -72,6 +95,9 @@ func (e *text) resolve(ps preset.Store) (err error) {
 		}
 	}
 
+	// named colors from the template
+	e.Color = cs.Resolve(e.Color)
+
 	return nil
 }
 
@@ -87,8 +113,13 @@ func (e *text) generateOutput(s *stamp.Stamp, as *args.Store) (err error) {
 		return nil // nothing to do here...
 	}
 
-	y2 := s.DeriveY2(e.Canvas, e.Y, e.Y2, e.Fontsize)
-	s.AddTextCell(e.Canvas, e.X, e.Y, e.X2, y2, e.Font, e.Fontsize, e.Align, *value, true)
+	style, err := getTextOutputStyle(e.Color)
+	if err != nil {
+		return err
+	}
+
+	y, y2 := s.DeriveY(e.Canvas, e.Y, e.Y2, e.Fontsize, e.Align)
+	s.AddTextCell(e.Canvas, e.X, y, e.X2, y2, e.Font, e.Fontstyle, e.Fontsize, e.Align, e.Rotation, *value, true, style)
 
 	return nil
 }
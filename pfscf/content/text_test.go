@@ -61,14 +61,66 @@ func TestTextCell_IsValid(t *testing.T) {
 			err := tc.isValid(&paramStore, &canvasStore)
 			test.ExpectError(t, err, "Canvas 'foobar' does not exist")
 		})
+
+		t.Run("invalid font style", func(t *testing.T) {
+			tc := getTextCellWithDummyData()
+			tc.Fontstyle = "BX"
+
+			err := tc.isValid(&paramStore, &canvasStore)
+			test.ExpectError(t, err, "Unknown font style 'BX'")
+		})
+
+		t.Run("invalid color", func(t *testing.T) {
+			tc := getTextCellWithDummyData()
+			tc.Color = "mauve"
+
+			err := tc.isValid(&paramStore, &canvasStore)
+			test.ExpectError(t, err, "Unknown color: 'mauve'")
+		})
+
+		t.Run("invalid alignment", func(t *testing.T) {
+			tc := getTextCellWithDummyData()
+			tc.Align = "CBT"
+
+			err := tc.isValid(&paramStore, &canvasStore)
+			test.ExpectError(t, err, "more than one vertical alignment")
+		})
+
+		t.Run("rotation without y2", func(t *testing.T) {
+			tc := getTextCellWithDummyData()
+			tc.Y2 = 0.0
+			tc.Rotation = 90.0
+
+			err := tc.isValid(&paramStore, &canvasStore)
+			test.ExpectError(t, err, "Rotated text requires coordinate Y2")
+		})
 	})
 
 	t.Run("valid", func(t *testing.T) {
-		tc := getTextCellWithDummyData()
-		tc.X = 0.0 // set something to "zero", which is also acceptable
+		t.Run("zero value", func(t *testing.T) {
+			tc := getTextCellWithDummyData()
+			tc.X = 0.0 // set something to "zero", which is also acceptable
 
-		err := tc.isValid(&paramStore, &canvasStore)
-		test.ExpectNoError(t, err)
+			err := tc.isValid(&paramStore, &canvasStore)
+			test.ExpectNoError(t, err)
+		})
+
+		t.Run("rotation", func(t *testing.T) {
+			tc := getTextCellWithDummyData()
+			tc.Rotation = -90.0
+
+			err := tc.isValid(&paramStore, &canvasStore)
+			test.ExpectNoError(t, err)
+		})
+
+		t.Run("font style and color", func(t *testing.T) {
+			tc := getTextCellWithDummyData()
+			tc.Fontstyle = "BI"
+			tc.Color = "ff8000"
+
+			err := tc.isValid(&paramStore, &canvasStore)
+			test.ExpectNoError(t, err)
+		})
 	})
 }
 
@@ -79,26 +131,38 @@ func TestTextCell_Resolve(t *testing.T) {
 		t.Run("non-existant preset", func(t *testing.T) {
 			tc := getTextCellWithDummyData("non-existing preset")
 
-			err := tc.resolve(*ps)
+			err := tc.resolve(*ps, *getTestColorStore())
 			test.ExpectError(t, err, "does not exist")
 		})
 
 		t.Run("conflicting presets", func(t *testing.T) {
 			tc := getTextCellWithDummyData("conflict1", "conflict2")
 
-			err := tc.resolve(*ps)
+			err := tc.resolve(*ps, *getTestColorStore())
 			test.ExpectError(t, err, "Contradicting values", "font", "conflict1", "conflict2")
 		})
 	})
 
 	t.Run("valid", func(t *testing.T) {
-		tc := getTextCellWithDummyData("sameData1", "sameData2")
-		tc.Font = ""
+		t.Run("presets", func(t *testing.T) {
+			tc := getTextCellWithDummyData("sameData1", "sameData2")
+			tc.Font = ""
 
-		err := tc.resolve(*ps)
-		test.ExpectNoError(t, err)
+			err := tc.resolve(*ps, *getTestColorStore())
+			test.ExpectNoError(t, err)
+
+			test.ExpectIsSet(t, tc.Font)
+		})
+
+		t.Run("named color", func(t *testing.T) {
+			tc := getTextCellWithDummyData()
+			tc.Color = "highlight"
+
+			err := tc.resolve(*ps, *getTestColorStore())
+			test.ExpectNoError(t, err)
 
-		test.ExpectIsSet(t, tc.Font)
+			test.ExpectEqual(t, tc.Color, "#ffcc00")
+		})
 	})
 }
 
@@ -115,6 +179,15 @@ func TestTextCell_generateOutput(t *testing.T) {
 
 		err := tc.generateOutput(stamp, as)
 		test.ExpectNoError(t, err)
+
+		tc.Fontstyle = "B"
+		tc.Color = "red"
+		err = tc.generateOutput(stamp, as)
+		test.ExpectNoError(t, err)
+
+		tc.Rotation = 90.0
+		err = tc.generateOutput(stamp, as)
+		test.ExpectNoError(t, err)
 	})
 }
 
@@ -3,6 +3,7 @@ package content
 import (
 	"github.com/Blesmol/pfscf/pfscf/args"
 	"github.com/Blesmol/pfscf/pfscf/canvas"
+	"github.com/Blesmol/pfscf/pfscf/color"
 	"github.com/Blesmol/pfscf/pfscf/param"
 	"github.com/Blesmol/pfscf/pfscf/preset"
 	"github.com/Blesmol/pfscf/pfscf/stamp"
@@ -33,8 +34,8 @@ func (e *trigger) isValid(paramStore *param.Store, canvasStore *canvas.Store) (e
 }
 
 // resolve the presets for this content object.
-func (e *trigger) resolve(ps preset.Store) (err error) {
-	return e.Content.Resolve(ps)
+func (e *trigger) resolve(ps preset.Store, cs color.Store) (err error) {
+	return e.Content.Resolve(ps, cs)
 }
 
 // generateOutput generates the output for this object.
@@ -0,0 +1,58 @@
+package expression
+
+import (
+	"fmt"
+
+	"github.com/Blesmol/pfscf/pfscf/utils"
+)
+
+// Lookup returns the value for the provided identifier, and whether the identifier is known.
+type Lookup func(name string) (value string, exists bool)
+
+// Expression is a parsed expression like `gp + items_sold - items_bought` that can be
+// evaluated with different values for the contained identifiers.
+type Expression struct {
+	input       string
+	root        node
+	identifiers []string
+}
+
+// Parse parses the provided input into an expression.
+func Parse(input string) (e *Expression, err error) {
+	tokens, err := tokenize(input)
+	if err != nil {
+		return nil, fmt.Errorf("Error parsing expression '%v': %v", input, err)
+	}
+
+	p := parser{tokens: tokens}
+	root, err := p.parseOr()
+	if err == nil && p.peek().typ != tokenEnd {
+		err = p.unexpected()
+	}
+	if err != nil {
+		return nil, fmt.Errorf("Error parsing expression '%v': %v", input, err)
+	}
+
+	e = &Expression{input: input, root: root}
+	for _, identifier := range p.identifiers {
+		if !utils.Contains(e.identifiers, identifier) {
+			e.identifiers = append(e.identifiers, identifier)
+		}
+	}
+	return e, nil
+}
+
+// Identifiers returns the identifiers used in the expression, in order of their first
+// appearance and without duplicates.
+func (e *Expression) Identifiers() []string {
+	return append(make([]string, 0, len(e.identifiers)), e.identifiers...)
+}
+
+// Evaluate evaluates the expression. The values of identifiers are retrieved using
+// the provided lookup function.
+func (e *Expression) Evaluate(lookup Lookup) (result Value, err error) {
+	if result, err = e.root.eval(lookup); err != nil {
+		return Value{}, fmt.Errorf("Error evaluating expression '%v': %v", e.input, err)
+	}
+	return result, nil
+}
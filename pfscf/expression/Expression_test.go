@@ -0,0 +1,159 @@
+package expression
+
+import (
+	"testing"
+
+	test "github.com/Blesmol/pfscf/pfscf/testutils"
+)
+
+func getTestLookup() Lookup {
+	values := map[string]string{
+		"gp":         "4.2",
+		"items_sold": "1.1",
+		"player":     "Bob",
+		"char":       "2001",
+		"empty":      "",
+		"notes[2]":   "foo",
+		"date.year":  "2020",
+	}
+	return func(name string) (value string, exists bool) {
+		value, exists = values[name]
+		return
+	}
+}
+
+func TestParse(t *testing.T) {
+	t.Run("errors", func(t *testing.T) {
+		testData := []struct{ input, expectedError string }{
+			{"", "Unexpected end of expression"},
+			{"gp +", "Unexpected end of expression"},
+			{"gp gp", "Unexpected 'gp' at position 4"},
+			{"(gp", "Unexpected end of expression"},
+			{"gp)", "Unexpected ')' at position 3"},
+			{"gp # 2", "Unexpected character '#' at position 4"},
+			{"'open", "Unexpected character '''"},
+			{"and gp", "Unexpected 'and' at position 1"},
+			{"foo(1)", "Unknown function 'foo' at position 1"},
+			{"if(1, 2)", "Wrong number of arguments for function 'if'"},
+			{"min()", "Wrong number of arguments for function 'min'"},
+			{"1 < 2 < 3", "Unexpected '<' at position 7"},
+		}
+
+		for _, tt := range testData {
+			t.Logf("Testing input '%v'", tt.input)
+			_, err := Parse(tt.input)
+			test.ExpectError(t, err, "Error parsing expression", tt.expectedError)
+		}
+	})
+
+	t.Run("identifiers", func(t *testing.T) {
+		e, err := Parse("gp + items_sold - gp & notes[2] & date.year & if(true, max(1, char), 0)")
+		test.ExpectNoError(t, err)
+
+		identifiers := e.Identifiers()
+		test.ExpectEqual(t, len(identifiers), 5)
+		for idx, expected := range []string{"gp", "items_sold", "notes[2]", "date.year", "char"} {
+			test.ExpectEqual(t, identifiers[idx], expected)
+		}
+	})
+}
+
+func TestExpression_Evaluate(t *testing.T) {
+	lookup := getTestLookup()
+
+	t.Run("errors", func(t *testing.T) {
+		testData := []struct{ input, expectedError string }{
+			{"unknown + 1", "Unknown parameter 'unknown'"},
+			{"player + 1", "Value 'Bob' is not a number"},
+			{"-player", "Value 'Bob' is not a number"},
+			{"gp / 0", "Division by zero"},
+			{"gp / empty", "Division by zero"},
+			{"true + 1", "Value 'true' is not a number"},
+		}
+
+		for _, tt := range testData {
+			t.Logf("Testing input '%v'", tt.input)
+			e, err := Parse(tt.input)
+			test.ExpectNoError(t, err)
+
+			_, err = e.Evaluate(lookup)
+			test.ExpectError(t, err, "Error evaluating expression", tt.expectedError)
+		}
+	})
+
+	t.Run("valid", func(t *testing.T) {
+		testData := []struct{ input, expected string }{
+			// arithmetic
+			{"gp + items_sold", "5.3"},
+			{"gp - items_sold * 2", "2"},
+			{"(gp - items_sold) * 2", "6.2"},
+			{"10 / 4", "2.5"},
+			{"-gp + 1", "-3.2"},
+			{"empty + 1", "1"},
+			{"gp - gp", "0"},
+			// concatenation
+			{"player & ' (' & char & ')'", "Bob (2001)"},
+			{`"#" & gp + items_sold`, "#5.3"},
+			{"notes[2] & date.year", "foo2020"},
+			// comparisons
+			{"gp > items_sold", "true"},
+			{"char == 2001", "true"},
+			{"char == '2001.0'", "true"},
+			{"player == 'Bob'", "true"},
+			{"player != 'Bob'", "false"},
+			{"player < 'Carl'", "true"},
+			{"empty == ''", "true"},
+			// logic
+			{"gp > 4 and player == 'Bob'", "true"},
+			{"gp > 5 or not empty", "true"},
+			{"not (gp > 5 or empty)", "true"},
+			{"empty and unknown", "false"},
+			{"player or unknown", "true"},
+			// functions
+			{"if(gp > 4, 'rich', 'poor')", "rich"},
+			{"if(empty, unknown, 'fallback')", "fallback"},
+			{"min(gp, items_sold, 3)", "1.1"},
+			{"max(gp, items_sold, 3)", "4.2"},
+			{"round(gp)", "4"},
+			{"round(10 / 3, 2)", "3.33"},
+		}
+
+		for _, tt := range testData {
+			t.Logf("Testing input '%v'", tt.input)
+			e, err := Parse(tt.input)
+			test.ExpectNoError(t, err)
+
+			result, err := e.Evaluate(lookup)
+			test.ExpectNoError(t, err)
+			test.ExpectEqual(t, result.String(), tt.expected)
+		}
+	})
+}
+
+func TestValue(t *testing.T) {
+	t.Run("number", func(t *testing.T) {
+		test.ExpectTrue(t, numberValue(1.5).IsNumber())
+		test.ExpectFalse(t, textValue("1.5").IsNumber())
+
+		number, err := textValue(" 1.5 ").Number()
+		test.ExpectNoError(t, err)
+		test.ExpectEqual(t, number, 1.5)
+
+		_, err = textValue("NaN").Number()
+		test.ExpectError(t, err, "Value 'NaN' is not a number")
+	})
+
+	t.Run("bool", func(t *testing.T) {
+		test.ExpectFalse(t, textValue(" ").Bool())
+		test.ExpectTrue(t, textValue("0").Bool())
+		test.ExpectFalse(t, numberValue(0.0).Bool())
+		test.ExpectTrue(t, numberValue(-1.0).Bool())
+	})
+
+	t.Run("string", func(t *testing.T) {
+		test.ExpectEqual(t, numberValue(4.2+1.1).String(), "5.3")
+		test.ExpectEqual(t, numberValue(-0.0000000001).String(), "0")
+		test.ExpectEqual(t, numberValue(1e6).String(), "1000000")
+		test.ExpectEqual(t, boolValue(false).String(), "false")
+	})
+}
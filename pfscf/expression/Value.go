@@ -0,0 +1,105 @@
+package expression
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+type kind int
+
+const (
+	kindText kind = iota
+	kindNumber
+	kindBool
+)
+
+// Value is the result of evaluating an expression or a part of it. It is either a
+// text, a number or a boolean value.
+type Value struct {
+	kind   kind
+	text   string
+	number float64
+	flag   bool
+}
+
+func textValue(text string) Value {
+	return Value{kind: kindText, text: text}
+}
+
+func numberValue(number float64) Value {
+	return Value{kind: kindNumber, number: number}
+}
+
+func boolValue(flag bool) Value {
+	return Value{kind: kindBool, flag: flag}
+}
+
+// IsNumber returns whether the value is a number.
+func (v Value) IsNumber() bool {
+	return v.kind == kindNumber
+}
+
+// Number returns the numeric value. Texts are converted to numbers, where an
+// empty text is taken as 0.
+func (v Value) Number() (number float64, err error) {
+	switch v.kind {
+	case kindNumber:
+		return v.number, nil
+	case kindText:
+		trimmed := strings.TrimSpace(v.text)
+		if trimmed == "" {
+			return 0.0, nil
+		}
+		if number, err = strconv.ParseFloat(trimmed, 64); err == nil && !math.IsNaN(number) && !math.IsInf(number, 0) {
+			return number, nil
+		}
+		return 0.0, fmt.Errorf("Value '%v' is not a number", v.text)
+	default:
+		return 0.0, fmt.Errorf("Value '%v' is not a number", v)
+	}
+}
+
+// isNumeric returns whether the value is a number or a text that contains a number.
+func (v Value) isNumeric() bool {
+	if v.kind == kindText && strings.TrimSpace(v.text) == "" {
+		return false
+	}
+	_, err := v.Number()
+	return err == nil
+}
+
+// Bool returns the value as boolean. Empty texts and the number 0 are false.
+func (v Value) Bool() bool {
+	switch v.kind {
+	case kindBool:
+		return v.flag
+	case kindNumber:
+		return v.number != 0.0
+	default:
+		return strings.TrimSpace(v.text) != ""
+	}
+}
+
+// String returns the textual representation of the value.
+func (v Value) String() string {
+	switch v.kind {
+	case kindNumber:
+		return formatNumber(v.number)
+	case kindBool:
+		return strconv.FormatBool(v.flag)
+	default:
+		return v.text
+	}
+}
+
+// formatNumber returns the number without superfluous decimal places. Rounding errors,
+// e.g. from 4.2+1.1, are removed.
+func formatNumber(number float64) string {
+	rounded := math.Round(number*1e9) / 1e9
+	if rounded == 0.0 {
+		rounded = 0.0 // no negative zero
+	}
+	return strconv.FormatFloat(rounded, 'f', -1, 64)
+}
@@ -0,0 +1,245 @@
+package expression
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/Blesmol/pfscf/pfscf/utils"
+)
+
+type node interface {
+	eval(lookup Lookup) (Value, error)
+}
+
+type literalNode struct {
+	value Value
+}
+
+func (n *literalNode) eval(Lookup) (Value, error) {
+	return n.value, nil
+}
+
+type identifierNode struct {
+	name string
+}
+
+func (n *identifierNode) eval(lookup Lookup) (Value, error) {
+	value, exists := lookup(n.name)
+	if !exists {
+		return Value{}, fmt.Errorf("Unknown parameter '%v'", n.name)
+	}
+	return textValue(value), nil
+}
+
+type negateNode struct {
+	operand node
+}
+
+func (n *negateNode) eval(lookup Lookup) (Value, error) {
+	value, err := evalNumber(n.operand, lookup)
+	if err != nil {
+		return Value{}, err
+	}
+	return numberValue(-value), nil
+}
+
+type notNode struct {
+	operand node
+}
+
+func (n *notNode) eval(lookup Lookup) (Value, error) {
+	value, err := n.operand.eval(lookup)
+	if err != nil {
+		return Value{}, err
+	}
+	return boolValue(!value.Bool()), nil
+}
+
+type binaryNode struct {
+	op          string
+	left, right node
+}
+
+func (n *binaryNode) eval(lookup Lookup) (result Value, err error) {
+	left, err := n.left.eval(lookup)
+	if err != nil {
+		return Value{}, err
+	}
+
+	// logical operators only evaluate the right side if required
+	switch n.op {
+	case "and":
+		if !left.Bool() {
+			return boolValue(false), nil
+		}
+		right, err := n.right.eval(lookup)
+		return boolValue(right.Bool()), err
+	case "or":
+		if left.Bool() {
+			return boolValue(true), nil
+		}
+		right, err := n.right.eval(lookup)
+		return boolValue(right.Bool()), err
+	}
+
+	right, err := n.right.eval(lookup)
+	if err != nil {
+		return Value{}, err
+	}
+
+	switch n.op {
+	case "&":
+		return textValue(left.String() + right.String()), nil
+	case "==", "!=", "<", "<=", ">", ">=":
+		return boolValue(compare(n.op, left, right)), nil
+	}
+
+	leftNumber, err := left.Number()
+	if err != nil {
+		return Value{}, err
+	}
+	rightNumber, err := right.Number()
+	if err != nil {
+		return Value{}, err
+	}
+
+	switch n.op {
+	case "+":
+		return numberValue(leftNumber + rightNumber), nil
+	case "-":
+		return numberValue(leftNumber - rightNumber), nil
+	case "*":
+		return numberValue(leftNumber * rightNumber), nil
+	case "/":
+		if rightNumber == 0.0 {
+			return Value{}, fmt.Errorf("Division by zero")
+		}
+		return numberValue(leftNumber / rightNumber), nil
+	}
+
+	utils.Assert(false, "Should be unreachable, or some valid case is missing here")
+	return Value{}, nil
+}
+
+// compare compares two values as numbers if both are numeric, or as texts otherwise.
+func compare(op string, left, right Value) bool {
+	var cmp int
+	if left.isNumeric() && right.isNumeric() {
+		l, _ := left.Number()
+		r, _ := right.Number()
+		switch {
+		case l < r:
+			cmp = -1
+		case l > r:
+			cmp = 1
+		}
+	} else {
+		l, r := left.String(), right.String()
+		switch {
+		case l < r:
+			cmp = -1
+		case l > r:
+			cmp = 1
+		}
+	}
+
+	switch op {
+	case "==":
+		return cmp == 0
+	case "!=":
+		return cmp != 0
+	case "<":
+		return cmp < 0
+	case "<=":
+		return cmp <= 0
+	case ">":
+		return cmp > 0
+	default:
+		return cmp >= 0
+	}
+}
+
+type callNode struct {
+	name string
+	args []node
+}
+
+func (n *callNode) eval(lookup Lookup) (Value, error) {
+	return functions[n.name].call(n.args, lookup)
+}
+
+type function struct {
+	minArgs, maxArgs int // maxArgs < 0 means unlimited
+	call             func(args []node, lookup Lookup) (Value, error)
+}
+
+var (
+	functions = map[string]function{
+		"if":    {3, 3, callIf},
+		"min":   {1, -1, callMin},
+		"max":   {1, -1, callMax},
+		"round": {1, 2, callRound},
+	}
+)
+
+func evalNumber(n node, lookup Lookup) (number float64, err error) {
+	value, err := n.eval(lookup)
+	if err != nil {
+		return 0.0, err
+	}
+	return value.Number()
+}
+
+// callIf returns the second argument if the first one is true, else the third.
+func callIf(args []node, lookup Lookup) (Value, error) {
+	cond, err := args[0].eval(lookup)
+	if err != nil {
+		return Value{}, err
+	}
+	if cond.Bool() {
+		return args[1].eval(lookup)
+	}
+	return args[2].eval(lookup)
+}
+
+func callMin(args []node, lookup Lookup) (Value, error) {
+	return callMinMax(args, lookup, math.Min)
+}
+
+func callMax(args []node, lookup Lookup) (Value, error) {
+	return callMinMax(args, lookup, math.Max)
+}
+
+func callMinMax(args []node, lookup Lookup, choose func(a, b float64) float64) (Value, error) {
+	result, err := evalNumber(args[0], lookup)
+	if err != nil {
+		return Value{}, err
+	}
+	for _, arg := range args[1:] {
+		number, err := evalNumber(arg, lookup)
+		if err != nil {
+			return Value{}, err
+		}
+		result = choose(result, number)
+	}
+	return numberValue(result), nil
+}
+
+// callRound rounds the first argument to the number of decimal places provided
+// as second argument, or to an integer number.
+func callRound(args []node, lookup Lookup) (Value, error) {
+	number, err := evalNumber(args[0], lookup)
+	if err != nil {
+		return Value{}, err
+	}
+
+	var places float64
+	if len(args) > 1 {
+		if places, err = evalNumber(args[1], lookup); err != nil {
+			return Value{}, err
+		}
+	}
+
+	factor := math.Pow(10, math.Trunc(places))
+	return numberValue(math.Round(number*factor) / factor), nil
+}
@@ -0,0 +1,314 @@
+package expression
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+type tokenType int
+
+const (
+	tokenNumber tokenType = iota
+	tokenText
+	tokenIdentifier
+	tokenOperator
+	tokenEnd
+)
+
+type token struct {
+	typ   tokenType
+	value string
+	pos   int
+}
+
+var (
+	regexToken = regexp.MustCompile(`^(?:` +
+		`(\d+(?:\.\d+)?|\.\d+)` + // number
+		`|"([^"]*)"|'([^']*)'` + // text in double or single quotes
+		`|([A-Za-z_][A-Za-z0-9_.]*(?:\[\d+\])?)` + // identifier, e.g. notes[2] or gp.total_cp
+		`|(==|!=|<=|>=|[-+*/&<>(),])` + // operator
+		`)`)
+)
+
+// tokenize splits the provided input into tokens.
+func tokenize(input string) (tokens []token, err error) {
+	pos := 0
+	for {
+		for pos < len(input) && strings.ContainsRune(" \t\r\n", rune(input[pos])) {
+			pos++
+		}
+		if pos >= len(input) {
+			break
+		}
+
+		match := regexToken.FindStringSubmatch(input[pos:])
+		if match == nil {
+			return nil, fmt.Errorf("Unexpected character '%c' at position %d", input[pos], pos+1)
+		}
+
+		t := token{pos: pos + 1}
+		switch {
+		case match[1] != "":
+			t.typ, t.value = tokenNumber, match[1]
+		case strings.HasPrefix(match[0], `"`):
+			t.typ, t.value = tokenText, match[2]
+		case strings.HasPrefix(match[0], `'`):
+			t.typ, t.value = tokenText, match[3]
+		case match[4] != "":
+			t.typ, t.value = tokenIdentifier, match[4]
+		default:
+			t.typ, t.value = tokenOperator, match[5]
+		}
+		tokens = append(tokens, t)
+		pos += len(match[0])
+	}
+
+	return append(tokens, token{typ: tokenEnd, pos: len(input) + 1}), nil
+}
+
+// parser is a recursive descent parser for expressions. From lowest to highest precedence,
+// it supports or, and, not, comparisons (== != < <= > >=), concatenation (&), addition
+// and subtraction (+ -), multiplication and division (* /), unary minus, and finally
+// numbers, texts, identifiers, function calls and parentheses.
+type parser struct {
+	tokens      []token
+	pos         int
+	identifiers []string
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) next() token {
+	t := p.tokens[p.pos]
+	if t.typ != tokenEnd {
+		p.pos++
+	}
+	return t
+}
+
+// accept consumes the next token if it is one of the provided operators or keywords.
+func (p *parser) accept(values ...string) (value string, accepted bool) {
+	t := p.peek()
+	if t.typ != tokenOperator && t.typ != tokenIdentifier {
+		return "", false
+	}
+	for _, v := range values {
+		if t.value == v {
+			p.next()
+			return v, true
+		}
+	}
+	return "", false
+}
+
+func (p *parser) expect(value string) error {
+	if _, ok := p.accept(value); !ok {
+		return p.unexpected()
+	}
+	return nil
+}
+
+func (p *parser) unexpected() error {
+	t := p.peek()
+	if t.typ == tokenEnd {
+		return fmt.Errorf("Unexpected end of expression")
+	}
+	return fmt.Errorf("Unexpected '%v' at position %d", t.value, t.pos)
+}
+
+func (p *parser) parseOr() (n node, err error) {
+	if n, err = p.parseAnd(); err != nil {
+		return nil, err
+	}
+	for {
+		if _, ok := p.accept("or"); !ok {
+			return n, nil
+		}
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		n = &binaryNode{"or", n, right}
+	}
+}
+
+func (p *parser) parseAnd() (n node, err error) {
+	if n, err = p.parseNot(); err != nil {
+		return nil, err
+	}
+	for {
+		if _, ok := p.accept("and"); !ok {
+			return n, nil
+		}
+		right, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		n = &binaryNode{"and", n, right}
+	}
+}
+
+func (p *parser) parseNot() (n node, err error) {
+	if _, ok := p.accept("not"); ok {
+		if n, err = p.parseNot(); err != nil {
+			return nil, err
+		}
+		return &notNode{n}, nil
+	}
+	return p.parseComparison()
+}
+
+func (p *parser) parseComparison() (n node, err error) {
+	if n, err = p.parseConcat(); err != nil {
+		return nil, err
+	}
+	if op, ok := p.accept("==", "!=", "<=", ">=", "<", ">"); ok {
+		right, err := p.parseConcat()
+		if err != nil {
+			return nil, err
+		}
+		n = &binaryNode{op, n, right}
+	}
+	return n, nil
+}
+
+func (p *parser) parseConcat() (n node, err error) {
+	if n, err = p.parseSum(); err != nil {
+		return nil, err
+	}
+	for {
+		if _, ok := p.accept("&"); !ok {
+			return n, nil
+		}
+		right, err := p.parseSum()
+		if err != nil {
+			return nil, err
+		}
+		n = &binaryNode{"&", n, right}
+	}
+}
+
+func (p *parser) parseSum() (n node, err error) {
+	if n, err = p.parseProduct(); err != nil {
+		return nil, err
+	}
+	for {
+		op, ok := p.accept("+", "-")
+		if !ok {
+			return n, nil
+		}
+		right, err := p.parseProduct()
+		if err != nil {
+			return nil, err
+		}
+		n = &binaryNode{op, n, right}
+	}
+}
+
+func (p *parser) parseProduct() (n node, err error) {
+	if n, err = p.parseUnary(); err != nil {
+		return nil, err
+	}
+	for {
+		op, ok := p.accept("*", "/")
+		if !ok {
+			return n, nil
+		}
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		n = &binaryNode{op, n, right}
+	}
+}
+
+func (p *parser) parseUnary() (n node, err error) {
+	if _, ok := p.accept("-"); ok {
+		if n, err = p.parseUnary(); err != nil {
+			return nil, err
+		}
+		return &negateNode{n}, nil
+	}
+	return p.parsePrimary()
+}
+
+func (p *parser) parsePrimary() (n node, err error) {
+	t := p.peek()
+
+	switch t.typ {
+	case tokenNumber:
+		p.next()
+		number, err := strconv.ParseFloat(t.value, 64)
+		if err != nil {
+			return nil, fmt.Errorf("Invalid number '%v' at position %d", t.value, t.pos)
+		}
+		return &literalNode{numberValue(number)}, nil
+	case tokenText:
+		p.next()
+		return &literalNode{textValue(t.value)}, nil
+	case tokenIdentifier:
+		switch t.value {
+		case "and", "or", "not":
+			return nil, p.unexpected()
+		case "true", "false":
+			p.next()
+			return &literalNode{boolValue(t.value == "true")}, nil
+		}
+		p.next()
+		if _, ok := p.accept("("); ok {
+			return p.parseCall(t)
+		}
+		p.identifiers = append(p.identifiers, t.value)
+		return &identifierNode{t.value}, nil
+	case tokenOperator:
+		if t.value == "(" {
+			p.next()
+			if n, err = p.parseOr(); err != nil {
+				return nil, err
+			}
+			if err = p.expect(")"); err != nil {
+				return nil, err
+			}
+			return n, nil
+		}
+	}
+
+	return nil, p.unexpected()
+}
+
+// parseCall parses the arguments of a function call. The opening parenthesis was already consumed.
+func (p *parser) parseCall(name token) (n node, err error) {
+	fct, exists := functions[name.value]
+	if !exists {
+		return nil, fmt.Errorf("Unknown function '%v' at position %d", name.value, name.pos)
+	}
+
+	args := make([]node, 0)
+	if _, ok := p.accept(")"); !ok {
+		for {
+			arg, err := p.parseOr()
+			if err != nil {
+				return nil, err
+			}
+			args = append(args, arg)
+
+			if _, ok := p.accept(","); ok {
+				continue
+			}
+			if err = p.expect(")"); err != nil {
+				return nil, err
+			}
+			break
+		}
+	}
+
+	if len(args) < fct.minArgs || (fct.maxArgs >= 0 && len(args) > fct.maxArgs) {
+		return nil, fmt.Errorf("Wrong number of arguments for function '%v' at position %d: %d", name.value, name.pos, len(args))
+	}
+
+	return &callNode{name.value, args}, nil
+}
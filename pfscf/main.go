@@ -19,9 +19,12 @@ func main() {
 	RootCmd := &cobra.Command{
 		Use:   "pfscf",
 		Short: "The Pathfinder Society Chronicle Filler (v" + version +")",
+
+		PersistentPreRun: cmd.ApplyConfigFiles,
 	}
 
 	RootCmd.PersistentFlags().BoolVarP(&cfg.Global.Verbose, "verbose", "v", false, "verbose output")
+	RootCmd.PersistentFlags().StringArrayVar(&cfg.Global.TemplateDirs, "template-dir", nil, "Additional directory to read templates from. Can be provided multiple times, later directories override templates with the same ID from earlier ones")
 
 	RootCmd.AddCommand(cmd.GetFillCommand())
 	RootCmd.AddCommand(cmd.GetTemplateCommand())
@@ -16,6 +16,8 @@ type Entry interface {
 	Example() string
 	Description() string
 	AcceptedValues() []string
+	Required() bool
+	Default() string
 	Group() string
 	setGroup(string)
 	rank() int
@@ -23,9 +25,67 @@ type Entry interface {
 	deepCopy() Entry
 	isValid() error
 	validateAndProcessArgs(*args.Store) error
+	argsFromValue(interface{}) (map[string]string, error)
 	describe(bool) string
 }
 
+// isSingleValue checks whether the provided value, as e.g. read from a values
+// file, is a single value and not a list or a map.
+func isSingleValue(value interface{}) bool {
+	switch value.(type) {
+	case []interface{}, map[interface{}]interface{}, map[string]interface{}, nil:
+		return false
+	}
+	return true
+}
+
+// UsageNote returns a short note on whether a value is required for the provided
+// entry or whether a default value is used, e.g. " (required)". If neither is the
+// case, an empty string is returned.
+func UsageNote(e Entry) string {
+	switch {
+	case e.Required():
+		return " (required)"
+	case utils.IsSet(e.Default()):
+		return fmt.Sprintf(" (default: %v)", utils.QuoteStringIfRequired(e.Default()))
+	}
+	return ""
+}
+
+// hasArgs checks whether a value is present in the arg store for any argument of the provided entry.
+func hasArgs(e Entry, as *args.Store) bool {
+	for _, argName := range e.ArgStoreIDs() {
+		if _, exists := as.Get(argName); exists {
+			return true
+		}
+	}
+	return false
+}
+
+// validateRequiredAndDefault checks the fields that are common to all entries. A default
+// value is checked in the same way as a value provided by the user.
+func validateRequiredAndDefault(e Entry) (err error) {
+	if !utils.IsSet(e.Default()) {
+		return nil
+	}
+	if e.Required() {
+		return fmt.Errorf("A required parameter cannot have a default value")
+	}
+
+	defaultArgs, err := e.argsFromValue(e.Default())
+	if err == nil {
+		as, _ := args.NewStore(args.StoreInit{})
+		for argName, argValue := range defaultArgs {
+			as.Set(argName, argValue)
+		}
+		err = e.validateAndProcessArgs(as)
+	}
+	if err != nil {
+		return fmt.Errorf("Invalid default value '%v': %v", e.Default(), err)
+	}
+	return nil
+}
+
 func genericContentUsageExample(id, exampleValue string) (result string) {
 	return fmt.Sprintf("%v=%v", id, utils.QuoteStringIfRequired(exampleValue))
 }
@@ -6,7 +6,9 @@ import (
 	"strings"
 
 	"github.com/Blesmol/pfscf/pfscf/args"
+	"github.com/Blesmol/pfscf/pfscf/expression"
 	"github.com/Blesmol/pfscf/pfscf/utils"
+	"github.com/Blesmol/pfscf/pfscf/yaml"
 )
 
 // Store stores a list of parameter descriptions
@@ -76,14 +78,89 @@ func (s *Store) InheritFrom(other *Store) (err error) {
 
 // IsValid checks whether all entries are valid.
 func (s *Store) IsValid() (err error) {
-	for _, entry := range *s {
-		if err = entry.isValid(); err != nil {
-			return fmt.Errorf("Error while validating parameter definition '%v': %v", entry.ID(), err)
-		}
+	if errs := s.ValidateAll(); len(errs) > 0 {
+		return errs[0]
 	}
 	return nil
 }
 
+// ValidateAll checks whether all entries are valid. In contrast to IsValid(), it does not
+// stop at the first error but returns all errors that were found. Each error is of type
+// *yaml.PathError and carries the group and ID of the affected parameter as path.
+func (s *Store) ValidateAll() (errs []error) {
+	for _, id := range s.GetKeysSortedByName() {
+		entry := (*s)[id]
+		err := entry.isValid()
+		if err == nil {
+			err = validateRequiredAndDefault(entry)
+		}
+		if err != nil {
+			err = fmt.Errorf("Error while validating parameter definition '%v': %v", entry.ID(), err)
+			errs = append(errs, yaml.NewPathError(err, entry.Group(), entry.ID()))
+		}
+	}
+
+	// computed parameters can only be checked in the context of the complete store
+	for _, id := range s.GetKeysSortedByName() {
+		computed, isComputed := (*s)[id].(*computedEntry)
+		if !isComputed || computed.isValid() != nil {
+			continue
+		}
+		if err := s.checkComputedEntry(computed); err != nil {
+			err = fmt.Errorf("Error while validating parameter definition '%v': %v", computed.ID(), err)
+			errs = append(errs, yaml.NewPathError(err, computed.Group(), computed.ID()))
+		}
+	}
+	return errs
+}
+
+// isKnownIdentifier checks whether the provided name can be used in an expression. This is the
+// case for parameter IDs, argument names like "notes[2]", and derived values like "gp.total_cp".
+func (s *Store) isKnownIdentifier(name string) bool {
+	if _, exists := s.Get(name); exists {
+		return true
+	}
+	if s.IsKnownArgument(name) {
+		return true
+	}
+	if idx := strings.Index(name, "."); idx > 0 {
+		_, exists := s.Get(name[:idx])
+		return exists
+	}
+	return false
+}
+
+// checkComputedEntry checks that the expression of a computed parameter only references known
+// parameters and does not depend on itself.
+func (s *Store) checkComputedEntry(entry *computedEntry) (err error) {
+	for _, identifier := range entry.identifiers() {
+		if !s.isKnownIdentifier(identifier) {
+			return fmt.Errorf("Expression references unknown parameter '%v'", identifier)
+		}
+	}
+
+	var visit func(current *computedEntry, path []string) error
+	visit = func(current *computedEntry, path []string) error {
+		for _, identifier := range current.identifiers() {
+			dependency, isComputed := (*s)[identifier].(*computedEntry)
+			if !isComputed {
+				continue
+			}
+			if dependency == entry {
+				return fmt.Errorf("Expression depends on itself: %v", strings.Join(append(path, identifier), " -> "))
+			}
+			if utils.Contains(path, identifier) {
+				continue // cycle not involving this entry, reported for the entries on the cycle
+			}
+			if err := visit(dependency, append(path, identifier)); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	return visit(entry, []string{entry.ID()})
+}
+
 func (s *Store) getArgNameToEntryMapping() (result map[string]Entry) {
 	result = make(map[string]Entry)
 
@@ -97,15 +174,23 @@ func (s *Store) getArgNameToEntryMapping() (result map[string]Entry) {
 }
 
 // ValidateAndProcessArgs checks whether all arguments in the arg store have a
-// corresponding parameter entry.
+// corresponding parameter entry. Default values are added for parameters without
+// value, and an error is returned if no value was provided for a required parameter.
 func (s *Store) ValidateAndProcessArgs(as *args.Store) (err error) {
 	argNameToEntry := s.getArgNameToEntryMapping()
 
+	if err = s.applyDefaults(as); err != nil {
+		return err
+	}
+
 	for _, argName := range as.GetKeys() {
 		paramEntry, pExists := argNameToEntry[argName]
 
 		// check that all entries in the arg store have a corresponding parameter entry
 		if !pExists {
+			if entry, exists := s.Get(argName); exists && entry.Type() == typeComputed {
+				return fmt.Errorf("Error while validating argument '%v': Values for computed parameters cannot be provided", argName)
+			}
 			return fmt.Errorf("Error while validating argument '%v': No corresponding parameter registered for template", argName)
 		}
 
@@ -115,9 +200,146 @@ func (s *Store) ValidateAndProcessArgs(as *args.Store) (err error) {
 		}
 	}
 
+	missing := make([]string, 0)
+	for _, id := range s.GetKeysSortedByName() {
+		if entry := (*s)[id]; entry.Required() && !hasArgs(entry, as) {
+			missing = append(missing, id)
+		}
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("Missing values for the following required parameters: %v", missing)
+	}
+
+	return s.computeArgs(as)
+}
+
+// applyDefaults adds the default values of all parameters to the arg store for which
+// no value was provided.
+func (s *Store) applyDefaults(as *args.Store) (err error) {
+	for _, id := range s.GetKeysSortedByName() {
+		entry := (*s)[id]
+		if !utils.IsSet(entry.Default()) || hasArgs(entry, as) {
+			continue
+		}
+
+		defaultArgs, err := entry.argsFromValue(entry.Default())
+		if err != nil {
+			return fmt.Errorf("Error while applying default value for parameter '%v': %v", id, err)
+		}
+		for argName, argValue := range defaultArgs {
+			as.Set(argName, argValue)
+		}
+	}
 	return nil
 }
 
+// computeArgs evaluates all computed parameters and adds their values to the arg store.
+// Computed parameters that are used by other computed parameters are evaluated first.
+func (s *Store) computeArgs(as *args.Store) (err error) {
+	done := make(map[string]bool)
+
+	var compute func(entry *computedEntry) error
+	compute = func(entry *computedEntry) error {
+		if done[entry.ID()] {
+			return nil
+		}
+		done[entry.ID()] = true // also protects against cycles, although these are already reported by ValidateAll()
+
+		for _, identifier := range entry.identifiers() {
+			if dependency, isComputed := (*s)[identifier].(*computedEntry); isComputed {
+				if err := compute(dependency); err != nil {
+					return err
+				}
+			}
+		}
+
+		value, err := entry.evaluate(s.expressionLookup(as))
+		if err != nil {
+			return fmt.Errorf("Error while computing parameter '%v': %v", entry.ID(), err)
+		}
+		as.Set(entry.ID(), value)
+		return nil
+	}
+
+	for _, id := range s.GetKeysSortedByName() {
+		if entry, isComputed := (*s)[id].(*computedEntry); isComputed {
+			if err = compute(entry); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// expressionLookup returns a lookup function for expressions that retrieves values from the
+// provided arg store. Parameters of type number and currency are looked up with their numeric
+// value, so that they can be used in calculations. Known parameters without value are treated
+// as empty.
+func (s *Store) expressionLookup(as *args.Store) expression.Lookup {
+	return func(name string) (value string, exists bool) {
+		if entry, isParam := s.Get(name); isParam {
+			switch entry.Type() {
+			case typeNumber:
+				name += ".value"
+			case typeCurrency:
+				name += ".total_gp"
+			}
+		}
+
+		if value, exists = as.Get(name); exists {
+			return value, true
+		}
+		return "", s.isKnownIdentifier(name)
+	}
+}
+
+// GetArgsFromValues converts a mapping from parameter IDs to values, as e.g. read from a values file,
+// into a mapping from argument names to argument values that can be added to an argument store.
+// Depending on the parameter type, lists of values are accepted as well, e.g. one entry per line for
+// multiline parameters. Single arguments like "notes[2]" can also be used as keys.
+func (s *Store) GetArgsFromValues(values map[string]interface{}) (result map[string]string, err error) {
+	argNameToEntry := s.getArgNameToEntryMapping()
+	result = make(map[string]string)
+
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		var entryArgs map[string]string
+		if paramEntry, exists := s.Get(key); exists {
+			if entryArgs, err = paramEntry.argsFromValue(values[key]); err != nil {
+				return nil, fmt.Errorf("Error while processing value for parameter '%v': %v", key, err)
+			}
+		} else if _, exists := argNameToEntry[key]; exists {
+			if !isSingleValue(values[key]) {
+				return nil, fmt.Errorf("Error while processing value for argument '%v': Expected a single value, but got '%v'", key, values[key])
+			}
+			entryArgs = map[string]string{key: fmt.Sprint(values[key])}
+		} else {
+			return nil, fmt.Errorf("Error while processing value for '%v': No corresponding parameter registered for template", key)
+		}
+
+		for argName, argValue := range entryArgs {
+			if _, exists := result[argName]; exists {
+				return nil, fmt.Errorf("Error while processing value for '%v': Multiple values provided for argument '%v'", key, argName)
+			}
+			result[argName] = argValue
+		}
+	}
+
+	return result, nil
+}
+
+// IsKnownArgument checks whether an argument with the provided name is accepted by any parameter.
+func (s *Store) IsKnownArgument(argName string) bool {
+	_, exists := s.getArgNameToEntryMapping()[argName]
+	return exists
+}
+
 // GetExampleArguments returns an array containing all keys and example values for all parameters.
 // The result can be passed to the ArgStore.
 func (s *Store) GetExampleArguments() (result []string) {
@@ -1,8 +1,10 @@
 package param
 
 import (
+	"strings"
 	"testing"
 
+	"github.com/Blesmol/pfscf/pfscf/args"
 	test "github.com/Blesmol/pfscf/pfscf/testutils"
 
 	"gopkg.in/yaml.v2"
@@ -89,3 +91,279 @@ Group2:
 		test.ExpectTrue(t, rankID2 < rankID3)
 	})
 }
+
+func TestStore_GetArgsFromValues(t *testing.T) {
+	yamlInput := []byte(`
+group:
+  text:
+    type: text
+    example: example
+  choice:
+    type: choice
+    description: desc
+    choices: [a, b, c]
+  lines:
+    type: multiline
+    example: example
+    description: desc
+    lines: 3
+`)
+	var store Store
+	test.ExpectNoError(t, yaml.Unmarshal(yamlInput, &store))
+
+	t.Run("errors", func(t *testing.T) {
+		for _, tc := range []struct {
+			values map[string]interface{}
+			errMsg string
+		}{
+			{map[string]interface{}{"unknown": "foo"}, "No corresponding parameter registered"},
+			{map[string]interface{}{"text": []interface{}{"a", "b"}}, "Expected a single value"},
+			{map[string]interface{}{"text": map[interface{}]interface{}{"a": "b"}}, "Expected a single value"},
+			{map[string]interface{}{"choice": []interface{}{"a", []interface{}{"b"}}}, "Expected a list of choices"},
+			{map[string]interface{}{"lines": []interface{}{"1", "2", "3", "4"}}, "Got 4 lines, but only 3 lines are available"},
+			{map[string]interface{}{"lines[2]": []interface{}{"a"}}, "Expected a single value"},
+			{map[string]interface{}{"lines": []interface{}{"a", "b"}, "lines[2]": "c"}, "Multiple values provided for argument 'lines[2]'"},
+		} {
+			t.Logf("Testing values %v", tc.values)
+			result, err := store.GetArgsFromValues(tc.values)
+			test.ExpectError(t, err, tc.errMsg)
+			test.ExpectNil(t, result)
+		}
+	})
+
+	t.Run("valid", func(t *testing.T) {
+		result, err := store.GetArgsFromValues(map[string]interface{}{
+			"text":   42,
+			"choice": []interface{}{"a", "c"},
+			"lines":  []interface{}{"line1", "", "line3"},
+		})
+		test.ExpectNoError(t, err)
+		test.ExpectEqual(t, len(result), 4)
+		test.ExpectEqual(t, result["text"], "42")
+		test.ExpectEqual(t, result["choice"], "a,c")
+		test.ExpectEqual(t, result["lines[1]"], "line1")
+		test.ExpectEqual(t, result["lines[3]"], "line3")
+
+		result, err = store.GetArgsFromValues(map[string]interface{}{
+			"choice": "b",
+			"lines":  "line1\nline2\n",
+		})
+		test.ExpectNoError(t, err)
+		test.ExpectEqual(t, len(result), 3)
+		test.ExpectEqual(t, result["choice"], "b")
+		test.ExpectEqual(t, result["lines[2]"], "line2")
+
+		result, err = store.GetArgsFromValues(map[string]interface{}{"lines[3]": "line3"})
+		test.ExpectNoError(t, err)
+		test.ExpectEqual(t, result["lines[3]"], "line3")
+	})
+}
+
+func getComputedTestStore(t *testing.T, computed string) (store Store) {
+	t.Helper()
+
+	yamlInput := []byte(`
+group:
+  player:
+    type: text
+    example: Bob
+    description: desc
+  xp:
+    type: number
+    example: 4
+    description: desc
+    unit: XP
+  gp:
+    type: currency
+    example: 10 gp
+    description: desc
+  lines:
+    type: multiline
+    example: example
+    description: desc
+    lines: 2
+computed:
+` + computed)
+	test.ExpectNoError(t, yaml.Unmarshal(yamlInput, &store))
+
+	return store
+}
+
+func TestStore_ValidateAll_Computed(t *testing.T) {
+	t.Run("errors", func(t *testing.T) {
+		testData := []struct{ computed, expectedError string }{
+			{`
+  total:
+    type: computed
+    description: desc
+    expression: gp + unknown`, "Expression references unknown parameter 'unknown'"},
+			{`
+  total:
+    type: computed
+    description: desc
+    expression: lines[3]`, "Expression references unknown parameter 'lines[3]'"},
+			{`
+  total:
+    type: computed
+    description: desc
+    expression: total + 1`, "Expression depends on itself: total -> total"},
+			{`
+  a:
+    type: computed
+    description: desc
+    expression: b
+  b:
+    type: computed
+    description: desc
+    expression: a`, "Expression depends on itself: a -> b -> a"},
+		}
+
+		for _, tt := range testData {
+			t.Logf("Testing: %v", tt.computed)
+			store := getComputedTestStore(t, tt.computed)
+			test.ExpectError(t, store.IsValid(), tt.expectedError)
+		}
+	})
+
+	t.Run("valid", func(t *testing.T) {
+		store := getComputedTestStore(t, `
+  total:
+    type: computed
+    description: desc
+    expression: gp + gp.total_cp + xp + lines[2] + player
+  summary:
+    type: computed
+    description: desc
+    expression: total`)
+		test.ExpectNoError(t, store.IsValid())
+	})
+}
+
+func TestStore_ValidateAndProcessArgs_Computed(t *testing.T) {
+	store := getComputedTestStore(t, `
+  summary:
+    type: computed
+    description: desc
+    expression: player & ' has ' & total
+  total:
+    type: computed
+    description: desc
+    expression: gp + xp * 2
+    format: "%.2f"`)
+	test.ExpectNoError(t, store.IsValid())
+
+	t.Run("errors", func(t *testing.T) {
+		as, err := args.NewStore(args.StoreInit{Args: []string{"total=5"}})
+		test.ExpectNoError(t, err)
+		err = store.ValidateAndProcessArgs(as)
+		test.ExpectError(t, err, "Error while validating argument 'total'", "Values for computed parameters cannot be provided")
+
+		as, err = args.NewStore(args.StoreInit{Args: []string{"player=Bob"}})
+		test.ExpectNoError(t, err)
+		store := getComputedTestStore(t, `
+  total:
+    type: computed
+    description: desc
+    expression: player * 2`)
+		err = store.ValidateAndProcessArgs(as)
+		test.ExpectError(t, err, "Error while computing parameter 'total'", "Value 'Bob' is not a number")
+	})
+
+	t.Run("valid", func(t *testing.T) {
+		as, err := args.NewStore(args.StoreInit{Args: []string{"player=Bob", "gp=4gp 2sp", "xp=2"}})
+		test.ExpectNoError(t, err)
+		test.ExpectNoError(t, store.ValidateAndProcessArgs(as))
+
+		total, _ := as.Get("total")
+		test.ExpectEqual(t, total, "8.20")
+		summary, _ := as.Get("summary")
+		test.ExpectEqual(t, summary, "Bob has 8.20")
+
+		// parameters without value are treated as empty
+		as, err = args.NewStore(args.StoreInit{Args: []string{"xp=1"}})
+		test.ExpectNoError(t, err)
+		test.ExpectNoError(t, store.ValidateAndProcessArgs(as))
+
+		summary, _ = as.Get("summary")
+		test.ExpectEqual(t, summary, " has 2.00")
+	})
+}
+
+func TestStore_RequiredAndDefault(t *testing.T) {
+	t.Run("invalid definitions", func(t *testing.T) {
+		testData := []struct{ yamlInput, expectedError, expectedDetail string }{
+			{"type: text\nexample: foo\ndescription: desc\nrequired: true\ndefault: bar", "A required parameter cannot have a default value", ""},
+			{"type: number\nexample: 4\ndescription: desc\nmax: 12\ndefault: 40", "Invalid default value '40'", "Provided value 40 is larger than the maximum of 12"},
+			{"type: choice\ndescription: desc\nchoices: [a, b]\ndefault: c", "Invalid default value 'c'", "Invalid choice 'c'"},
+			{"type: computed\ndescription: desc\nexpression: 1\nrequired: true", "Computed parameters cannot be required or have a default value", ""},
+		}
+
+		for _, tt := range testData {
+			t.Logf("Testing: %v", tt.yamlInput)
+			var store Store
+			test.ExpectNoError(t, yaml.Unmarshal([]byte("group:\n  id:\n    "+strings.ReplaceAll(tt.yamlInput, "\n", "\n    ")), &store))
+			test.ExpectError(t, store.IsValid(), "Error while validating parameter definition 'id'", tt.expectedError, tt.expectedDetail)
+		}
+	})
+
+	yamlInput := []byte(`
+group:
+  societyid:
+    type: societyid
+    example: 123456-2001
+    description: desc
+    required: true
+  xp:
+    type: number
+    example: 4
+    description: desc
+    unit: XP
+    default: 4
+  lines:
+    type: multiline
+    example: example
+    description: desc
+    lines: 2
+    default: "line1\nline2"
+`)
+	var store Store
+	test.ExpectNoError(t, yaml.Unmarshal(yamlInput, &store))
+	test.ExpectNoError(t, store.IsValid())
+
+	societyid, _ := store.Get("societyid")
+	test.ExpectTrue(t, societyid.Required())
+	test.ExpectEqual(t, UsageNote(societyid), " (required)")
+	xp, _ := store.Get("xp")
+	test.ExpectFalse(t, xp.Required())
+	test.ExpectEqual(t, xp.Default(), "4")
+	test.ExpectEqual(t, UsageNote(xp), " (default: 4)")
+
+	t.Run("errors", func(t *testing.T) {
+		as, err := args.NewStore(args.StoreInit{Args: []string{"xp=2"}})
+		test.ExpectNoError(t, err)
+		err = store.ValidateAndProcessArgs(as)
+		test.ExpectError(t, err, "Missing values for the following required parameters: [societyid]")
+	})
+
+	t.Run("valid", func(t *testing.T) {
+		as, err := args.NewStore(args.StoreInit{Args: []string{"societyid=123456-2001"}})
+		test.ExpectNoError(t, err)
+		test.ExpectNoError(t, store.ValidateAndProcessArgs(as))
+
+		// default values are processed like provided values
+		xp, _ := as.Get("xp")
+		test.ExpectEqual(t, xp, "4 XP")
+		line, _ := as.Get("lines[2]")
+		test.ExpectEqual(t, line, "line2")
+
+		// provided values take precedence over default values
+		as, err = args.NewStore(args.StoreInit{Args: []string{"societyid=123456-2001", "xp=2", "lines[1]=foo"}})
+		test.ExpectNoError(t, err)
+		test.ExpectNoError(t, store.ValidateAndProcessArgs(as))
+
+		xp, _ = as.Get("xp")
+		test.ExpectEqual(t, xp, "2 XP")
+		_, exists := as.Get("lines[2]")
+		test.ExpectFalse(t, exists)
+	})
+}
@@ -13,7 +13,7 @@ const (
 )
 
 type choiceEntry struct {
-	commonFields
+	commonFields `yaml:",inline"`
 
 	TheExample     string   `yaml:"example"`
 	TheDescription string   `yaml:"description"`
@@ -75,14 +75,30 @@ func (e *choiceEntry) validateAndProcessArgs(as *args.Store) error {
 	return nil
 }
 
+func (e *choiceEntry) argsFromValue(value interface{}) (result map[string]string, err error) {
+	list, isList := value.([]interface{})
+	if !isList {
+		return e.commonFields.argsFromValue(value)
+	}
+
+	choices := make([]string, 0, len(list))
+	for _, entry := range list {
+		if !isSingleValue(entry) {
+			return nil, fmt.Errorf("Expected a list of choices, but got '%v'", value)
+		}
+		choices = append(choices, fmt.Sprint(entry))
+	}
+	return map[string]string{e.id: strings.Join(choices, ",")}, nil
+}
+
 func (e *choiceEntry) describe(verbose bool) (result string) {
 	var sb strings.Builder
 
 	if !verbose {
-		fmt.Fprintf(&sb, "- %v: %v\n", e.id, e.Description())
+		fmt.Fprintf(&sb, "- %v: %v%v\n", e.id, e.Description(), UsageNote(e))
 	} else {
 		fmt.Fprintf(&sb, "- %v\n", e.id)
-		fmt.Fprintf(&sb, "\tDesc: %v\n", e.Description())
+		fmt.Fprintf(&sb, "\tDesc: %v%v\n", e.Description(), UsageNote(e))
 		fmt.Fprintf(&sb, "\tType: %v\n", e.Type())
 		fmt.Fprintf(&sb, "\tAllowed Choices: %v\n", e.TheChoices)
 		fmt.Fprintf(&sb, "\tExample: %v\n", genericContentUsageExample(e.id, e.Example()))
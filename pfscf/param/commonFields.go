@@ -1,11 +1,18 @@
 package param
 
-import "github.com/Blesmol/pfscf/pfscf/utils"
+import (
+	"fmt"
+
+	"github.com/Blesmol/pfscf/pfscf/utils"
+)
 
 type commonFields struct {
 	id      string
 	group   string
 	theRank int
+
+	TheRequired bool   `yaml:"required"`
+	TheDefault  string `yaml:"default"`
 }
 
 func (e *commonFields) ID() string {
@@ -17,10 +24,27 @@ func (e *commonFields) setID(id string) {
 	e.id = id
 }
 
+func (e *commonFields) Required() bool {
+	return e.TheRequired
+}
+
+func (e *commonFields) Default() string {
+	return e.TheDefault
+}
+
 func (e *commonFields) ArgStoreIDs() []string {
 	return []string{e.id}
 }
 
+// argsFromValue converts a value, as e.g. read from a values file, into a mapping from
+// argument names to argument values. By default, only single values are accepted.
+func (e *commonFields) argsFromValue(value interface{}) (result map[string]string, err error) {
+	if !isSingleValue(value) {
+		return nil, fmt.Errorf("Expected a single value, but got '%v'", value)
+	}
+	return map[string]string{e.id: fmt.Sprint(value)}, nil
+}
+
 func (e *commonFields) Group() string {
 	return e.group
 }
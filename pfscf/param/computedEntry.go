@@ -0,0 +1,123 @@
+package param
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Blesmol/pfscf/pfscf/args"
+	"github.com/Blesmol/pfscf/pfscf/expression"
+	"github.com/Blesmol/pfscf/pfscf/utils"
+)
+
+const (
+	typeComputed = "computed"
+)
+
+// computedEntry is a parameter whose value is not provided by the user, but computed
+// from the values of other parameters.
+type computedEntry struct {
+	commonFields `yaml:",inline"`
+
+	TheDescription string `yaml:"description"`
+	Expression     string `yaml:"expression"`
+	Format         string `yaml:"format"` // printf-like pattern for numeric results, e.g. "%.2f"
+}
+
+func (e *computedEntry) Type() string {
+	return typeComputed
+}
+
+// ArgStoreIDs returns an empty list, as no values can be provided for computed parameters.
+func (e *computedEntry) ArgStoreIDs() []string {
+	return []string{}
+}
+
+func (e *computedEntry) Example() string {
+	return ""
+}
+
+func (e *computedEntry) Description() string {
+	return e.TheDescription
+}
+
+func (e *computedEntry) AcceptedValues() []string {
+	return []string{"Computed as " + e.Expression}
+}
+
+func (e *computedEntry) deepCopy() Entry {
+	copy := *e
+	return &copy
+}
+
+func (e *computedEntry) isValid() (err error) {
+	if !utils.IsSet(e.TheDescription) {
+		return fmt.Errorf("Missing description")
+	}
+	if !utils.IsSet(e.Expression) {
+		return fmt.Errorf("Missing expression")
+	}
+	if e.Required() || utils.IsSet(e.Default()) {
+		return fmt.Errorf("Computed parameters cannot be required or have a default value")
+	}
+	if _, err = expression.Parse(e.Expression); err != nil {
+		return err
+	}
+	if utils.IsSet(e.Format) {
+		if formatted := fmt.Sprintf(e.Format, 0.0); strings.Contains(formatted, "%!") {
+			return fmt.Errorf("Format '%v' cannot be used for a single number: '%v'", e.Format, formatted)
+		}
+	}
+	return nil
+}
+
+// identifiers returns the names of all parameters and arguments used in the expression.
+func (e *computedEntry) identifiers() []string {
+	parsed, err := expression.Parse(e.Expression)
+	if err != nil {
+		return []string{} // reported by isValid()
+	}
+	return parsed.Identifiers()
+}
+
+// evaluate computes the value of this parameter.
+func (e *computedEntry) evaluate(lookup expression.Lookup) (result string, err error) {
+	parsed, err := expression.Parse(e.Expression)
+	if err != nil {
+		return "", err
+	}
+
+	value, err := parsed.Evaluate(lookup)
+	if err != nil {
+		return "", err
+	}
+
+	if utils.IsSet(e.Format) {
+		if number, err := value.Number(); err == nil {
+			return fmt.Sprintf(e.Format, number), nil
+		}
+	}
+	return value.String(), nil
+}
+
+func (e *computedEntry) validateAndProcessArgs(*args.Store) error {
+	return fmt.Errorf("Values for computed parameters cannot be provided")
+}
+
+func (e *computedEntry) argsFromValue(interface{}) (map[string]string, error) {
+	return nil, fmt.Errorf("Values for computed parameters cannot be provided")
+}
+
+func (e *computedEntry) describe(verbose bool) (result string) {
+	var sb strings.Builder
+
+	if !verbose {
+		fmt.Fprintf(&sb, "- %v: %v%v\n", e.id, e.Description(), UsageNote(e))
+	} else {
+		fmt.Fprintf(&sb, "- %v\n", e.id)
+		fmt.Fprintf(&sb, "\tDesc: %v%v\n", e.Description(), UsageNote(e))
+		fmt.Fprintf(&sb, "\tType: %v\n", e.Type())
+		fmt.Fprintf(&sb, "\tExpression: %v\n", e.Expression)
+	}
+
+	return sb.String()
+}
@@ -0,0 +1,86 @@
+package param
+
+import (
+	"testing"
+
+	test "github.com/Blesmol/pfscf/pfscf/testutils"
+
+	"gopkg.in/yaml.v2"
+)
+
+func getComputedEntryFromYAML(t *testing.T, yamlInput string) (e *computedEntry) {
+	t.Helper()
+
+	e = &computedEntry{}
+	err := yaml.Unmarshal([]byte(yamlInput), e)
+	test.ExpectNoError(t, err)
+	e.setID("gp_total")
+
+	return e
+}
+
+func TestComputedEntry_YAML(t *testing.T) {
+	e := getComputedEntryFromYAML(t, `
+description: Total gold
+expression: gp + items_sold
+format: "%.2f"
+`)
+	test.ExpectEqual(t, e.Type(), "computed")
+	test.ExpectEqual(t, e.Example(), "")
+	test.ExpectEqual(t, len(e.ArgStoreIDs()), 0)
+	test.ExpectEqual(t, e.Description(), "Total gold")
+	test.ExpectEqual(t, e.Expression, "gp + items_sold")
+	test.ExpectEqual(t, e.Format, "%.2f")
+	test.ExpectNoError(t, e.isValid())
+
+	identifiers := e.identifiers()
+	test.ExpectEqual(t, len(identifiers), 2)
+	test.ExpectEqual(t, identifiers[0], "gp")
+	test.ExpectEqual(t, identifiers[1], "items_sold")
+}
+
+func TestComputedEntry_isValid(t *testing.T) {
+	testData := []struct{ title, yamlInput, expectedError string }{
+		{"missing description", "expression: gp", "Missing description"},
+		{"missing expression", "description: foo", "Missing expression"},
+		{"invalid expression", "description: foo\nexpression: gp +", "Error parsing expression 'gp +'"},
+		{"format for text", "description: foo\nexpression: gp\nformat: '%s'", "Format '%s' cannot be used"},
+	}
+
+	for _, tt := range testData {
+		t.Logf("Testing: %v", tt.title)
+		e := getComputedEntryFromYAML(t, tt.yamlInput)
+		test.ExpectError(t, e.isValid(), tt.expectedError)
+	}
+}
+
+func TestComputedEntry_evaluate(t *testing.T) {
+	lookup := func(name string) (string, bool) {
+		values := map[string]string{"gp": "4.2", "items_sold": "1.1", "player": "Bob"}
+		value, exists := values[name]
+		return value, exists
+	}
+
+	t.Run("errors", func(t *testing.T) {
+		e := getComputedEntryFromYAML(t, "description: foo\nexpression: gp + player")
+		_, err := e.evaluate(lookup)
+		test.ExpectError(t, err, "Value 'Bob' is not a number")
+	})
+
+	t.Run("valid", func(t *testing.T) {
+		testData := []struct{ yamlInput, expected string }{
+			{"expression: gp + items_sold", "5.3"},
+			{"expression: gp + items_sold\nformat: '%.2f'", "5.30"},
+			{"expression: player & ' (' & gp & ')'\nformat: '%.2f'", "Bob (4.2)"},
+			{"expression: if(gp > 5, 'yes', 'no')", "no"},
+		}
+
+		for _, tt := range testData {
+			t.Logf("Testing: %v", tt.yamlInput)
+			e := getComputedEntryFromYAML(t, "description: foo\n"+tt.yamlInput)
+			result, err := e.evaluate(lookup)
+			test.ExpectNoError(t, err)
+			test.ExpectEqual(t, result, tt.expected)
+		}
+	})
+}
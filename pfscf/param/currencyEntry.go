@@ -0,0 +1,157 @@
+package param
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/Blesmol/pfscf/pfscf/args"
+	"github.com/Blesmol/pfscf/pfscf/utils"
+)
+
+const (
+	typeCurrency = "currency"
+)
+
+var (
+	regexCurrencyPart = regexp.MustCompile(`(\d+(?:\.\d+)?|\.\d+)\s*([a-zA-Z]*)`)
+
+	// value of each denomination in copper pieces
+	currencyFactors = map[string]int64{"pp": 1000, "gp": 100, "sp": 10, "cp": 1}
+)
+
+type currencyEntry struct {
+	commonFields `yaml:",inline"`
+
+	TheExample     string `yaml:"example"`
+	TheDescription string `yaml:"description"`
+}
+
+func (e *currencyEntry) Type() string {
+	return typeCurrency
+}
+
+func (e *currencyEntry) Example() string {
+	return e.TheExample
+}
+
+func (e *currencyEntry) Description() string {
+	return e.TheDescription
+}
+
+func (e *currencyEntry) AcceptedValues() []string {
+	return []string{"Amounts in pp/gp/sp/cp like \"4gp 2sp\", or numbers in gp like \"4.2\""}
+}
+
+func (e *currencyEntry) deepCopy() Entry {
+	copy := *e
+	return &copy
+}
+
+func (e *currencyEntry) isValid() (err error) {
+	if !utils.IsSet(e.TheExample) {
+		return fmt.Errorf("Missing example")
+	}
+	if !utils.IsSet(e.TheDescription) {
+		return fmt.Errorf("Missing description")
+	}
+	if _, err = parseCurrency(e.TheExample); err != nil {
+		return fmt.Errorf("Invalid example: %v", err)
+	}
+	return nil
+}
+
+// parseCurrency converts the provided amount of money into copper pieces. The amount
+// may consist of several parts with denominations pp, gp, sp and cp, e.g. "4gp 2sp".
+// A single number without denomination is taken as gp.
+func parseCurrency(input string) (totalCp int64, err error) {
+	parts := regexCurrencyPart.FindAllStringSubmatch(input, -1)
+	separators := regexCurrencyPart.ReplaceAllString(input, "")
+	if len(parts) == 0 || strings.Trim(separators, " \t,") != "" {
+		return 0, fmt.Errorf("Provided value is not an amount of money: '%v'. Expected something like '4gp 2sp' or '4.2'", input)
+	}
+
+	seen := make(map[string]bool)
+	for _, part := range parts {
+		denomination := strings.ToLower(part[2])
+		if !utils.IsSet(denomination) {
+			if len(parts) > 1 {
+				return 0, fmt.Errorf("Amount '%v' in '%v' has no denomination like 'gp' or 'sp'", part[1], input)
+			}
+			denomination = "gp"
+		}
+
+		factor, known := currencyFactors[denomination]
+		if !known {
+			return 0, fmt.Errorf("Unknown denomination '%v' in '%v'. Supported are pp, gp, sp and cp", part[2], input)
+		}
+		if seen[denomination] {
+			return 0, fmt.Errorf("Denomination '%v' is provided more than once in '%v'", denomination, input)
+		}
+		seen[denomination] = true
+
+		amount, err := strconv.ParseFloat(part[1], 64)
+		utils.AssertNoError(err) // guaranteed by regex
+
+		cp := amount * float64(factor)
+		if math.Abs(cp-math.Round(cp)) > 1e-6 {
+			return 0, fmt.Errorf("Amount '%v' cannot be paid in whole copper pieces", strings.TrimSpace(part[0]))
+		}
+		totalCp += int64(math.Round(cp))
+	}
+
+	return totalCp, nil
+}
+
+// normalizeCurrency returns the provided amount of copper pieces in gp, sp and cp,
+// e.g. "4 gp 2 sp".
+func normalizeCurrency(totalCp int64) string {
+	parts := make([]string, 0)
+	for _, denomination := range []string{"gp", "sp", "cp"} {
+		factor := currencyFactors[denomination]
+		if amount := totalCp / factor; amount > 0 {
+			parts = append(parts, fmt.Sprintf("%d %v", amount, denomination))
+		}
+		totalCp %= factor
+	}
+
+	if len(parts) == 0 {
+		return "0 gp"
+	}
+	return strings.Join(parts, " ")
+}
+
+func (e *currencyEntry) validateAndProcessArgs(as *args.Store) (err error) {
+	argValue, exists := as.Get(e.ID())
+	utils.Assert(exists, "Existence of entry should have been validated by caller")
+
+	totalCp, err := parseCurrency(argValue)
+	if err != nil {
+		return err
+	}
+
+	// add to arg store
+	as.Set(e.ID()+".total_cp", strconv.FormatInt(totalCp, 10))
+	as.Set(e.ID()+".total_gp", strconv.FormatFloat(float64(totalCp)/100.0, 'f', -1, 64))
+	as.Set(e.ID()+".normalized", normalizeCurrency(totalCp))
+
+	return nil
+}
+
+func (e *currencyEntry) describe(verbose bool) (result string) {
+	var sb strings.Builder
+
+	if !verbose {
+		fmt.Fprintf(&sb, "- %v: %v%v\n", e.id, e.Description(), UsageNote(e))
+	} else {
+		fmt.Fprintf(&sb, "- %v\n", e.id)
+		fmt.Fprintf(&sb, "\tDesc: %v%v\n", e.Description(), UsageNote(e))
+		fmt.Fprintf(&sb, "\tType: %v\n", e.Type())
+		fmt.Fprintf(&sb, "\tAllowed Values: %v\n", e.AcceptedValues()[0])
+		fmt.Fprintf(&sb, "\tExample: %v\n", genericContentUsageExample(e.id, e.Example()))
+	}
+
+	return sb.String()
+}
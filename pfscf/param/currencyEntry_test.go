@@ -0,0 +1,120 @@
+package param
+
+import (
+	"testing"
+
+	"github.com/Blesmol/pfscf/pfscf/args"
+	test "github.com/Blesmol/pfscf/pfscf/testutils"
+
+	"gopkg.in/yaml.v2"
+)
+
+func TestCurrencyEntry_YAML(t *testing.T) {
+	yamlInput := `
+example: 4gp 2sp
+description: Gold gained
+`
+	var e currencyEntry
+
+	err := yaml.Unmarshal([]byte(yamlInput), &e)
+	test.ExpectNoError(t, err)
+	test.ExpectEqual(t, e.Type(), "currency")
+	test.ExpectEqual(t, e.Example(), "4gp 2sp")
+	test.ExpectEqual(t, e.Description(), "Gold gained")
+	test.ExpectNoError(t, e.isValid())
+}
+
+func TestCurrencyEntry_isValid(t *testing.T) {
+	t.Run("errors", func(t *testing.T) {
+		test.ExpectError(t, (&currencyEntry{TheDescription: "foo"}).isValid(), "Missing example")
+		test.ExpectError(t, (&currencyEntry{TheExample: "4"}).isValid(), "Missing description")
+		test.ExpectError(t, (&currencyEntry{TheExample: "4 ducats", TheDescription: "foo"}).isValid(), "Invalid example")
+	})
+
+	t.Run("valid", func(t *testing.T) {
+		test.ExpectNoError(t, (&currencyEntry{TheExample: "4.2", TheDescription: "foo"}).isValid())
+	})
+}
+
+func TestParseCurrency(t *testing.T) {
+	t.Run("errors", func(t *testing.T) {
+		testData := []struct{ input, expectedError string }{
+			{"", "Provided value is not an amount of money: ''"},
+			{"four gp", "Provided value is not an amount of money"},
+			{"-4gp", "Provided value is not an amount of money"},
+			{"4 ducats", "Unknown denomination 'ducats' in '4 ducats'"},
+			{"4gp 2", "Amount '2' in '4gp 2' has no denomination"},
+			{"4gp 2gp", "Denomination 'gp' is provided more than once in '4gp 2gp'"},
+			{"4.255", "Amount '4.255' cannot be paid in whole copper pieces"},
+			{"1.5cp", "Amount '1.5cp' cannot be paid in whole copper pieces"},
+		}
+
+		for _, tt := range testData {
+			t.Logf("Testing input '%v'", tt.input)
+			_, err := parseCurrency(tt.input)
+			test.ExpectError(t, err, tt.expectedError)
+		}
+	})
+
+	t.Run("valid", func(t *testing.T) {
+		testData := []struct {
+			input   string
+			totalCp int64
+		}{
+			{"4", 400},
+			{" 4.2 ", 420},
+			{".5", 50},
+			{"42sp", 420},
+			{"4gp 2sp", 420},
+			{"4 GP, 2 sp, 3cp", 423},
+			{"1pp 1cp", 1001},
+			{"0", 0},
+		}
+
+		for _, tt := range testData {
+			t.Logf("Testing input '%v'", tt.input)
+			totalCp, err := parseCurrency(tt.input)
+			test.ExpectNoError(t, err)
+			test.ExpectEqual(t, totalCp, tt.totalCp)
+		}
+	})
+}
+
+func TestCurrencyEntry_validateAndProcessArgs(t *testing.T) {
+	e := currencyEntry{TheExample: "4", TheDescription: "foo"}
+	e.setID("gp")
+
+	t.Run("errors", func(t *testing.T) {
+		as, err := args.NewStore(args.StoreInit{Args: []string{"gp=lots"}})
+		test.ExpectNoError(t, err)
+
+		err = e.validateAndProcessArgs(as)
+		test.ExpectError(t, err, "Provided value is not an amount of money: 'lots'")
+	})
+
+	t.Run("valid", func(t *testing.T) {
+		testData := []struct{ input, totalCp, totalGp, normalized string }{
+			{"4.2", "420", "4.2", "4 gp 2 sp"},
+			{"42sp", "420", "4.2", "4 gp 2 sp"},
+			{"1pp 3cp", "1003", "10.03", "10 gp 3 cp"},
+			{"0", "0", "0", "0 gp"},
+		}
+
+		for _, tt := range testData {
+			t.Logf("Testing input '%v'", tt.input)
+			as, err := args.NewStore(args.StoreInit{Args: []string{"gp=" + tt.input}})
+			test.ExpectNoError(t, err)
+
+			test.ExpectNoError(t, e.validateAndProcessArgs(as))
+
+			value, _ := as.Get("gp")
+			test.ExpectEqual(t, value, tt.input)
+			value, _ = as.Get("gp.total_cp")
+			test.ExpectEqual(t, value, tt.totalCp)
+			value, _ = as.Get("gp.total_gp")
+			test.ExpectEqual(t, value, tt.totalGp)
+			value, _ = as.Get("gp.normalized")
+			test.ExpectEqual(t, value, tt.normalized)
+		}
+	})
+}
@@ -0,0 +1,231 @@
+package param
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Blesmol/pfscf/pfscf/args"
+	"github.com/Blesmol/pfscf/pfscf/utils"
+)
+
+const (
+	typeDate = "date"
+
+	defaultDateFormat = "YYYY-MM-DD"
+	defaultDateLocale = "en-US"
+)
+
+type dateLocale struct {
+	dayFirst     bool // for dates with slashes, e.g. 02/01/2006
+	months       [12]string
+	monthRegexes [12]*regexp.Regexp // matches localized month names; nil where equal to the English name
+}
+
+func newDateLocale(dayFirst bool, months [12]string) (l dateLocale) {
+	l = dateLocale{dayFirst: dayFirst, months: months}
+	for idx, month := range months {
+		if month != englishMonths[idx] {
+			l.monthRegexes[idx] = regexp.MustCompile(`(?i)\b` + regexp.QuoteMeta(month) + `\b`)
+		}
+	}
+	return l
+}
+
+var (
+	englishMonths = [12]string{"January", "February", "March", "April", "May", "June", "July", "August", "September", "October", "November", "December"}
+	germanMonths  = [12]string{"Januar", "Februar", "März", "April", "Mai", "Juni", "Juli", "August", "September", "Oktober", "November", "Dezember"}
+
+	dateLocales = map[string]dateLocale{
+		"en-US": newDateLocale(false, englishMonths),
+		"en-GB": newDateLocale(true, englishMonths),
+		"de-DE": newDateLocale(true, germanMonths),
+	}
+	validDateLocales = []string{"de-DE", "en-GB", "en-US"}
+
+	regexDateToken = regexp.MustCompile(`YYYY|YY|MMMM|MMM|MM|M|DD|D`)
+	regexSlashDate = regexp.MustCompile(`^(\d{1,2})/(\d{1,2})/\d{4}$`)
+
+	// timeNow returns the current time, used for keywords like "today"
+	timeNow = time.Now
+)
+
+type dateEntry struct {
+	commonFields `yaml:",inline"`
+
+	TheExample     string `yaml:"example"`
+	TheDescription string `yaml:"description"`
+	Format         string `yaml:"format"` // output format, e.g. "DD.MM.YYYY"
+	Locale         string `yaml:"locale"` // order of day and month in input, names of months
+}
+
+func (e *dateEntry) Type() string {
+	return typeDate
+}
+
+func (e *dateEntry) Example() string {
+	return e.TheExample
+}
+
+func (e *dateEntry) Description() string {
+	return e.TheDescription
+}
+
+func (e *dateEntry) AcceptedValues() []string {
+	if !utils.IsSet(e.Locale) {
+		return []string{"Dates like 2020-06-27 or 27.06.2020 or June 27 2020; or today/yesterday"}
+	}
+	if dateLocales[e.locale()].dayFirst {
+		return []string{"Dates like 2020-06-27 or 27.06.2020 or 27/06/2020 or 27 June 2020; or today/yesterday"}
+	}
+	return []string{"Dates like 2020-06-27 or 27.06.2020 or 06/27/2020 or June 27 2020; or today/yesterday"}
+}
+
+func (e *dateEntry) deepCopy() Entry {
+	copy := *e
+	return &copy
+}
+
+func (e *dateEntry) locale() string {
+	if utils.IsSet(e.Locale) {
+		return e.Locale
+	}
+	return defaultDateLocale
+}
+
+func (e *dateEntry) outputFormat() string {
+	if utils.IsSet(e.Format) {
+		return e.Format
+	}
+	return defaultDateFormat
+}
+
+func (e *dateEntry) isValid() (err error) {
+	if !utils.IsSet(e.TheExample) {
+		return fmt.Errorf("Missing example")
+	}
+	if !utils.IsSet(e.TheDescription) {
+		return fmt.Errorf("Missing description")
+	}
+	if _, exists := dateLocales[e.locale()]; !exists {
+		return fmt.Errorf("Unknown locale '%v'. Supported locales are %v", e.Locale, validDateLocales)
+	}
+	if _, err = e.parse(e.TheExample); err != nil {
+		return fmt.Errorf("Invalid example: %v", err)
+	}
+	return nil
+}
+
+// parse converts the provided input into a date. Besides ISO dates, dates with dots
+// are read as day first, and dates with slashes depend on the locale. If no locale is
+// set, dates with slashes are only accepted if the order of day and month is clear.
+func (e *dateEntry) parse(input string) (date time.Time, err error) {
+	locale := dateLocales[e.locale()]
+	value := strings.TrimSpace(input)
+
+	now := timeNow()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	switch strings.ToLower(value) {
+	case "today":
+		return today, nil
+	case "yesterday":
+		return today.AddDate(0, 0, -1), nil
+	}
+
+	// localized month names are translated, as Go only parses English ones
+	for idx, regexMonth := range locale.monthRegexes {
+		if regexMonth != nil {
+			value = regexMonth.ReplaceAllString(value, englishMonths[idx])
+		}
+	}
+
+	layouts := []string{"2006-01-02", "2.1.2006"}
+	if locale.dayFirst {
+		layouts = append(layouts, "2/1/2006", "2 January 2006", "2. January 2006", "2 Jan 2006")
+	} else {
+		layouts = append(layouts, "1/2/2006", "January 2 2006", "January 2, 2006", "Jan 2 2006", "Jan 2, 2006")
+	}
+
+	if !utils.IsSet(e.Locale) {
+		if match := regexSlashDate.FindStringSubmatch(value); match != nil {
+			first, _ := strconv.Atoi(match[1])
+			second, _ := strconv.Atoi(match[2])
+			if first != second && first <= 12 && second <= 12 {
+				return date, fmt.Errorf("Provided date '%v' is ambiguous, as day and month could be swapped. Use something like '2020-06-27' or '27.06.2020' instead", input)
+			}
+			layouts = append(layouts, "2/1/2006")
+		}
+	}
+
+	for _, layout := range layouts {
+		if date, err = time.Parse(layout, value); err == nil {
+			return date, nil
+		}
+	}
+
+	return date, fmt.Errorf("Provided value is not a valid date: '%v'. Expected something like '2020-06-27', '27.06.2020' or 'today'", input)
+}
+
+// format returns the provided date in the output format of this parameter.
+func (e *dateEntry) format(date time.Time) string {
+	locale := dateLocales[e.locale()]
+
+	return regexDateToken.ReplaceAllStringFunc(e.outputFormat(), func(token string) string {
+		switch token {
+		case "YYYY":
+			return fmt.Sprintf("%04d", date.Year())
+		case "YY":
+			return fmt.Sprintf("%02d", date.Year()%100)
+		case "MMMM":
+			return locale.months[date.Month()-1]
+		case "MMM":
+			return string([]rune(locale.months[date.Month()-1])[:3])
+		case "MM":
+			return fmt.Sprintf("%02d", int(date.Month()))
+		case "M":
+			return fmt.Sprintf("%d", int(date.Month()))
+		case "DD":
+			return fmt.Sprintf("%02d", date.Day())
+		default:
+			return fmt.Sprintf("%d", date.Day())
+		}
+	})
+}
+
+func (e *dateEntry) validateAndProcessArgs(as *args.Store) (err error) {
+	argValue, exists := as.Get(e.ID())
+	utils.Assert(exists, "Existence of entry should have been validated by caller")
+
+	date, err := e.parse(argValue)
+	if err != nil {
+		return err
+	}
+
+	// add to arg store
+	as.Set(e.ID(), e.format(date))
+	as.Set(e.ID()+".year", fmt.Sprintf("%04d", date.Year()))
+	as.Set(e.ID()+".month", fmt.Sprintf("%02d", int(date.Month())))
+	as.Set(e.ID()+".day", fmt.Sprintf("%02d", date.Day()))
+	as.Set(e.ID()+".iso", date.Format("2006-01-02"))
+
+	return nil
+}
+
+func (e *dateEntry) describe(verbose bool) (result string) {
+	var sb strings.Builder
+
+	if !verbose {
+		fmt.Fprintf(&sb, "- %v: %v%v\n", e.id, e.Description(), UsageNote(e))
+	} else {
+		fmt.Fprintf(&sb, "- %v\n", e.id)
+		fmt.Fprintf(&sb, "\tDesc: %v%v\n", e.Description(), UsageNote(e))
+		fmt.Fprintf(&sb, "\tType: %v\n", e.Type())
+		fmt.Fprintf(&sb, "\tAllowed Values: %v\n", e.AcceptedValues()[0])
+		fmt.Fprintf(&sb, "\tOutput Format: %v\n", e.outputFormat())
+		fmt.Fprintf(&sb, "\tExample: %v\n", genericContentUsageExample(e.id, e.Example()))
+	}
+
+	return sb.String()
+}
@@ -0,0 +1,153 @@
+package param
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Blesmol/pfscf/pfscf/args"
+	test "github.com/Blesmol/pfscf/pfscf/testutils"
+
+	"gopkg.in/yaml.v2"
+)
+
+func TestDateEntry_YAML(t *testing.T) {
+	yamlInput := `
+example: 27.06.2020
+description: Date of the session
+format: DD.MM.YYYY
+locale: de-DE
+`
+	var e dateEntry
+
+	err := yaml.Unmarshal([]byte(yamlInput), &e)
+	test.ExpectNoError(t, err)
+	test.ExpectEqual(t, e.Type(), "date")
+	test.ExpectEqual(t, e.Example(), "27.06.2020")
+	test.ExpectEqual(t, e.Description(), "Date of the session")
+	test.ExpectEqual(t, e.Format, "DD.MM.YYYY")
+	test.ExpectEqual(t, e.Locale, "de-DE")
+	test.ExpectNoError(t, e.isValid())
+}
+
+func TestDateEntry_isValid(t *testing.T) {
+	t.Run("errors", func(t *testing.T) {
+		test.ExpectError(t, (&dateEntry{TheDescription: "foo"}).isValid(), "Missing example")
+		test.ExpectError(t, (&dateEntry{TheExample: "today"}).isValid(), "Missing description")
+		test.ExpectError(t, (&dateEntry{TheExample: "today", TheDescription: "foo", Locale: "xx-XX"}).isValid(), "Unknown locale 'xx-XX'")
+		test.ExpectError(t, (&dateEntry{TheExample: "31.02.2020", TheDescription: "foo"}).isValid(), "Invalid example")
+	})
+
+	t.Run("valid", func(t *testing.T) {
+		test.ExpectNoError(t, (&dateEntry{TheExample: "27.06.2020", TheDescription: "foo"}).isValid())
+	})
+}
+
+func TestDateEntry_parse(t *testing.T) {
+	formerTimeNow := timeNow
+	defer func() { timeNow = formerTimeNow }()
+	timeNow = func() time.Time { return time.Date(2021, time.March, 1, 23, 30, 0, 0, time.Local) }
+
+	t.Run("errors", func(t *testing.T) {
+		testData := []struct{ locale, input string }{
+			{"", "tomorrow"},
+			{"", "27.13.2020"},
+			{"", "29.02.2021"},
+			{"en-US", "27/06/2020"}, // month first
+			{"en-GB", "06/27/2020"},
+			{"", "2020-6-27"},
+			{"", "27 Juni 2020"},
+		}
+
+		for _, tt := range testData {
+			t.Logf("Testing input '%v' with locale '%v'", tt.input, tt.locale)
+			e := dateEntry{Locale: tt.locale}
+			_, err := e.parse(tt.input)
+			test.ExpectError(t, err, "Provided value is not a valid date")
+		}
+
+		// without locale, the order of day and month must be clear
+		for _, input := range []string{"05/06/2020", "12/01/2020"} {
+			t.Logf("Testing ambiguous input '%v'", input)
+			_, err := (&dateEntry{}).parse(input)
+			test.ExpectError(t, err, "Provided date '"+input+"' is ambiguous")
+		}
+	})
+
+	t.Run("valid", func(t *testing.T) {
+		testData := []struct{ locale, input, expected string }{
+			{"", "2020-06-27", "2020-06-27"},
+			{"", "27.06.2020", "2020-06-27"},
+			{"", " 7.6.2020 ", "2020-06-07"},
+			{"en-US", "06/07/2020", "2020-06-07"},
+			{"en-GB", "06/07/2020", "2020-07-06"},
+			{"", "06/27/2020", "2020-06-27"},
+			{"", "27/06/2020", "2020-06-27"},
+			{"", "05/05/2020", "2020-05-05"},
+			{"", "June 27, 2020", "2020-06-27"},
+			{"", "jun 27 2020", "2020-06-27"},
+			{"en-GB", "27 June 2020", "2020-06-27"},
+			{"de-DE", "27. März 2020", "2020-03-27"},
+			{"de-DE", "27 mai 2020", "2020-05-27"},
+			{"", "29.02.2020", "2020-02-29"},
+			{"", "today", "2021-03-01"},
+			{"", "Yesterday", "2021-02-28"},
+		}
+
+		for _, tt := range testData {
+			t.Logf("Testing input '%v' with locale '%v'", tt.input, tt.locale)
+			e := dateEntry{Locale: tt.locale}
+			date, err := e.parse(tt.input)
+			test.ExpectNoError(t, err)
+			test.ExpectEqual(t, date.Format("2006-01-02"), tt.expected)
+		}
+	})
+}
+
+func TestDateEntry_format(t *testing.T) {
+	date := time.Date(2020, time.March, 7, 0, 0, 0, 0, time.UTC)
+
+	testData := []struct{ format, locale, expected string }{
+		{"", "", "2020-03-07"},
+		{"DD.MM.YYYY", "", "07.03.2020"},
+		{"M/D/YY", "", "3/7/20"},
+		{"D MMMM YYYY", "", "7 March 2020"},
+		{"D. MMMM YYYY", "de-DE", "7. März 2020"},
+		{"MMM D, YYYY", "de-DE", "Mär 7, 2020"},
+	}
+
+	for _, tt := range testData {
+		e := dateEntry{Format: tt.format, Locale: tt.locale}
+		test.ExpectEqual(t, e.format(date), tt.expected)
+	}
+}
+
+func TestDateEntry_validateAndProcessArgs(t *testing.T) {
+	e := dateEntry{TheExample: "today", TheDescription: "foo", Format: "DD.MM.YYYY"}
+	e.setID("date")
+
+	t.Run("errors", func(t *testing.T) {
+		as, err := args.NewStore(args.StoreInit{Args: []string{"date=someday"}})
+		test.ExpectNoError(t, err)
+
+		err = e.validateAndProcessArgs(as)
+		test.ExpectError(t, err, "Provided value is not a valid date: 'someday'")
+	})
+
+	t.Run("valid", func(t *testing.T) {
+		as, err := args.NewStore(args.StoreInit{Args: []string{"date=2020-06-27"}})
+		test.ExpectNoError(t, err)
+
+		test.ExpectNoError(t, e.validateAndProcessArgs(as))
+
+		for key, expected := range map[string]string{
+			"date":       "27.06.2020",
+			"date.year":  "2020",
+			"date.month": "06",
+			"date.day":   "27",
+			"date.iso":   "2020-06-27",
+		} {
+			value, _ := as.Get(key)
+			test.ExpectEqual(t, value, expected)
+		}
+	})
+}
@@ -40,10 +40,26 @@ func (ey *entryYAML) UnmarshalYAML(unmarshal func(interface{}) error) (err error
 		var e choiceEntry
 		err = unmarshal(&e)
 		ey.e = &e
+	case typeCurrency:
+		var e currencyEntry
+		err = unmarshal(&e)
+		ey.e = &e
+	case typeDate:
+		var e dateEntry
+		err = unmarshal(&e)
+		ey.e = &e
 	case typeMultiline:
 		var e multilineEntry
 		err = unmarshal(&e)
 		ey.e = &e
+	case typeNumber:
+		var e numberEntry
+		err = unmarshal(&e)
+		ey.e = &e
+	case typeComputed:
+		var e computedEntry
+		err = unmarshal(&e)
+		ey.e = &e
 	default:
 		err = fmt.Errorf("Unknown parameter type: '%v'", ety.Type)
 	}
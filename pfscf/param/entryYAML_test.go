@@ -31,22 +31,34 @@ func TestEntryYAML_UnmarshalYAML(t *testing.T) {
 	})
 
 	t.Run("valid", func(t *testing.T) {
-		testData := []struct{ typeName string }{
-			{typeText},
-			{typeSocietyID},
+		testData := []struct{ typeName, yamlInput, expectedExample string }{
+			{typeText, "example: some example", "some example"},
+			{typeSocietyID, "example: some example", "some example"},
+			{typeChoice, "example: some example\nchoices: [a, b]", "some example"},
+			{typeMultiline, "example: some example\nlines: 3", "some example"},
+			{typeNumber, "example: some example", "some example"},
+			{typeCurrency, "example: some example", "some example"},
+			{typeDate, "example: some example", "some example"},
+			{typeComputed, "expression: gp + 1", ""},
 		}
 
 		for _, tt := range testData {
 			t.Logf("Testing: %v", tt.typeName)
 
-			yamlInput := fmt.Sprintf("type: %v\nexample: some example", tt.typeName)
+			yamlInput := fmt.Sprintf("type: %v\ndescription: some description\n%v", tt.typeName, tt.yamlInput)
 
 			var entry entryYAML
 			err := yaml.Unmarshal([]byte(yamlInput), &entry)
 
 			test.ExpectNoError(t, err)
 			test.ExpectEqual(t, entry.e.Type(), tt.typeName)
-			test.ExpectEqual(t, entry.e.Example(), "some example")
+			test.ExpectEqual(t, entry.e.Example(), tt.expectedExample)
+			test.ExpectEqual(t, entry.e.Description(), "some description")
 		}
+
+		var entry entryYAML
+		err := yaml.Unmarshal([]byte("type: computed\ndescription: foo\nexpression: gp + 1"), &entry)
+		test.ExpectNoError(t, err)
+		test.ExpectEqual(t, entry.e.(*computedEntry).Expression, "gp + 1")
 	})
 }
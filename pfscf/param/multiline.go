@@ -13,7 +13,7 @@ const (
 )
 
 type multilineEntry struct {
-	commonFields
+	commonFields `yaml:",inline"`
 
 	TheExample     string `yaml:"example"`
 	TheDescription string `yaml:"description"`
@@ -87,14 +87,42 @@ func (e *multilineEntry) validateAndProcessArgs(as *args.Store) error {
 	return nil
 }
 
+func (e *multilineEntry) argsFromValue(value interface{}) (result map[string]string, err error) {
+	var lines []interface{}
+	if list, isList := value.([]interface{}); isList {
+		lines = list
+	} else if isSingleValue(value) {
+		for _, line := range strings.Split(strings.TrimRight(fmt.Sprint(value), "\n"), "\n") {
+			lines = append(lines, line)
+		}
+	} else {
+		return nil, fmt.Errorf("Expected a single value or a list of lines, but got '%v'", value)
+	}
+
+	if len(lines) > e.NumLines {
+		return nil, fmt.Errorf("Got %d lines, but only %d lines are available", len(lines), e.NumLines)
+	}
+
+	result = make(map[string]string)
+	for idx, line := range lines {
+		if !isSingleValue(line) {
+			return nil, fmt.Errorf("Expected a list of lines, but got '%v'", value)
+		}
+		if lineStr := fmt.Sprint(line); utils.IsSet(lineStr) {
+			result[fmt.Sprintf("%v[%v]", e.id, idx+1)] = lineStr
+		}
+	}
+	return result, nil
+}
+
 func (e *multilineEntry) describe(verbose bool) (result string) {
 	var sb strings.Builder
 
 	if !verbose {
-		fmt.Fprintf(&sb, "- %v: %v\n", e.id, e.Description())
+		fmt.Fprintf(&sb, "- %v: %v%v\n", e.id, e.Description(), UsageNote(e))
 	} else {
 		fmt.Fprintf(&sb, "- %v\n", e.id)
-		fmt.Fprintf(&sb, "\tDesc: %v\n", e.Description())
+		fmt.Fprintf(&sb, "\tDesc: %v%v\n", e.Description(), UsageNote(e))
 		fmt.Fprintf(&sb, "\tType: %v\n", e.Type())
 		fmt.Fprintf(&sb, "\tLines: %v\n", e.NumLines)
 		fmt.Fprintf(&sb, "\tExample: %v\n", genericContentUsageExample(e.id, e.Example()))
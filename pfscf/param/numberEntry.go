@@ -0,0 +1,173 @@
+package param
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/Blesmol/pfscf/pfscf/args"
+	"github.com/Blesmol/pfscf/pfscf/utils"
+)
+
+const (
+	typeNumber = "number"
+)
+
+type numberEntry struct {
+	commonFields `yaml:",inline"`
+
+	TheExample     string   `yaml:"example"`
+	TheDescription string   `yaml:"description"`
+	Min            *float64 `yaml:"min"`
+	Max            *float64 `yaml:"max"`
+	Decimal        bool     `yaml:"decimal"` // allow numbers with decimal places, otherwise only integers
+	Unit           string   `yaml:"unit"`    // optional on input, appended on output
+	Format         string   `yaml:"format"`  // printf-like pattern for the number, e.g. "%03d" or "%.2f"
+}
+
+func (e *numberEntry) Type() string {
+	return typeNumber
+}
+
+func (e *numberEntry) Example() string {
+	return e.TheExample
+}
+
+func (e *numberEntry) Description() string {
+	return e.TheDescription
+}
+
+func (e *numberEntry) AcceptedValues() []string {
+	var sb strings.Builder
+
+	if e.Decimal {
+		fmt.Fprintf(&sb, "Numbers")
+	} else {
+		fmt.Fprintf(&sb, "Integer numbers")
+	}
+
+	switch {
+	case e.Min != nil && e.Max != nil:
+		fmt.Fprintf(&sb, " between %v and %v", *e.Min, *e.Max)
+	case e.Min != nil:
+		fmt.Fprintf(&sb, " of at least %v", *e.Min)
+	case e.Max != nil:
+		fmt.Fprintf(&sb, " of at most %v", *e.Max)
+	}
+
+	if utils.IsSet(e.Unit) {
+		fmt.Fprintf(&sb, ", optionally followed by '%v'", e.Unit)
+	}
+
+	return []string{sb.String()}
+}
+
+func (e *numberEntry) deepCopy() Entry {
+	copy := *e
+
+	if e.Min != nil {
+		min := *e.Min
+		copy.Min = &min
+	}
+	if e.Max != nil {
+		max := *e.Max
+		copy.Max = &max
+	}
+
+	return &copy
+}
+
+func (e *numberEntry) isValid() (err error) {
+	if !utils.IsSet(e.TheExample) {
+		return fmt.Errorf("Missing example")
+	}
+	if !utils.IsSet(e.TheDescription) {
+		return fmt.Errorf("Missing description")
+	}
+	if e.Min != nil && e.Max != nil && *e.Min > *e.Max {
+		return fmt.Errorf("Minimum %v is larger than maximum %v", *e.Min, *e.Max)
+	}
+	if utils.IsSet(e.Format) {
+		if formatted := e.format(0.0); strings.Contains(formatted, "%!") {
+			return fmt.Errorf("Format '%v' cannot be used for a single number: '%v'", e.Format, formatted)
+		}
+	}
+	if _, err = e.parse(e.TheExample); err != nil {
+		return fmt.Errorf("Invalid example: %v", err)
+	}
+	return nil
+}
+
+// parse converts the provided input into a number and checks it against the
+// restrictions of this parameter.
+func (e *numberEntry) parse(input string) (value float64, err error) {
+	trimmed := strings.TrimSpace(input)
+	if utils.IsSet(e.Unit) {
+		trimmed = strings.TrimSpace(strings.TrimSuffix(trimmed, e.Unit))
+	}
+
+	value, err = strconv.ParseFloat(trimmed, 64)
+	if err != nil || math.IsNaN(value) || math.IsInf(value, 0) {
+		return 0.0, fmt.Errorf("Provided value is not a number: '%v'", input)
+	}
+	if !e.Decimal && value != math.Trunc(value) {
+		return 0.0, fmt.Errorf("Provided value is not an integer number: '%v'", input)
+	}
+	if e.Min != nil && value < *e.Min {
+		return 0.0, fmt.Errorf("Provided value %v is smaller than the minimum of %v", value, *e.Min)
+	}
+	if e.Max != nil && value > *e.Max {
+		return 0.0, fmt.Errorf("Provided value %v is larger than the maximum of %v", value, *e.Max)
+	}
+
+	return value, nil
+}
+
+// format returns the textual representation of the provided number, including the unit.
+func (e *numberEntry) format(value float64) (result string) {
+	switch {
+	case utils.IsSet(e.Format) && e.Decimal:
+		result = fmt.Sprintf(e.Format, value)
+	case utils.IsSet(e.Format):
+		result = fmt.Sprintf(e.Format, int64(value))
+	default:
+		result = strconv.FormatFloat(value, 'f', -1, 64)
+	}
+
+	if utils.IsSet(e.Unit) {
+		result += " " + e.Unit
+	}
+	return result
+}
+
+func (e *numberEntry) validateAndProcessArgs(as *args.Store) (err error) {
+	argValue, exists := as.Get(e.ID())
+	utils.Assert(exists, "Existence of entry should have been validated by caller")
+
+	value, err := e.parse(argValue)
+	if err != nil {
+		return err
+	}
+
+	as.Set(e.ID(), e.format(value))
+	as.Set(e.ID()+".value", strconv.FormatFloat(value, 'f', -1, 64))
+
+	return nil
+}
+
+func (e *numberEntry) describe(verbose bool) (result string) {
+	var sb strings.Builder
+
+	if !verbose {
+		fmt.Fprintf(&sb, "- %v: %v%v\n", e.id, e.Description(), UsageNote(e))
+	} else {
+		fmt.Fprintf(&sb, "- %v\n", e.id)
+		fmt.Fprintf(&sb, "\tDesc: %v%v\n", e.Description(), UsageNote(e))
+		fmt.Fprintf(&sb, "\tType: %v\n", e.Type())
+		fmt.Fprintf(&sb, "\tAllowed Values: %v\n", e.AcceptedValues()[0])
+		fmt.Fprintf(&sb, "\tExample: %v\n", genericContentUsageExample(e.id, e.Example()))
+	}
+
+	return sb.String()
+}
@@ -0,0 +1,152 @@
+package param
+
+import (
+	"testing"
+
+	"github.com/Blesmol/pfscf/pfscf/args"
+	test "github.com/Blesmol/pfscf/pfscf/testutils"
+
+	"gopkg.in/yaml.v2"
+)
+
+func getNumberEntryFromYAML(t *testing.T, yamlInput string) (e *numberEntry) {
+	t.Helper()
+
+	e = &numberEntry{}
+	err := yaml.Unmarshal([]byte(yamlInput), e)
+	test.ExpectNoError(t, err)
+	e.setID("xp")
+
+	return e
+}
+
+func TestNumberEntry_YAML(t *testing.T) {
+	e := getNumberEntryFromYAML(t, `
+example: 4
+description: XP earned
+min: 0
+max: 12
+decimal: true
+unit: XP
+format: "%.1f"
+`)
+	test.ExpectEqual(t, e.Type(), "number")
+	test.ExpectEqual(t, e.Example(), "4")
+	test.ExpectEqual(t, e.Description(), "XP earned")
+	test.ExpectEqual(t, *e.Min, 0.0)
+	test.ExpectEqual(t, *e.Max, 12.0)
+	test.ExpectTrue(t, e.Decimal)
+	test.ExpectEqual(t, e.Unit, "XP")
+	test.ExpectEqual(t, e.Format, "%.1f")
+	test.ExpectNoError(t, e.isValid())
+}
+
+func TestNumberEntry_isValid(t *testing.T) {
+	t.Run("errors", func(t *testing.T) {
+		testData := []struct{ title, yamlInput, expectedError string }{
+			{"missing example", "description: foo", "Missing example"},
+			{"missing description", "example: 1", "Missing description"},
+			{"min larger than max", "example: 1\ndescription: foo\nmin: 5\nmax: 4", "Minimum 5 is larger than maximum 4"},
+			{"format with two numbers", "example: 1\ndescription: foo\nformat: '%d-%d'", "Format '%d-%d' cannot be used"},
+			{"format for text", "example: 1\ndescription: foo\nformat: '%s'", "Format '%s' cannot be used"},
+			{"example out of range", "example: 20\ndescription: foo\nmax: 12", "Invalid example"},
+			{"example not an integer", "example: 1.5\ndescription: foo", "Invalid example"},
+		}
+
+		for _, tt := range testData {
+			t.Logf("Testing: %v", tt.title)
+			e := getNumberEntryFromYAML(t, tt.yamlInput)
+			test.ExpectError(t, e.isValid(), tt.expectedError)
+		}
+	})
+
+	t.Run("valid", func(t *testing.T) {
+		e := getNumberEntryFromYAML(t, "example: 4\ndescription: foo\nmin: 0\nformat: '%02d'")
+		test.ExpectNoError(t, e.isValid())
+	})
+}
+
+func TestNumberEntry_validateAndProcessArgs(t *testing.T) {
+	process := func(e *numberEntry, value string) (result string, err error) {
+		as, err := args.NewStore(args.StoreInit{Args: []string{"xp=" + value}})
+		test.ExpectNoError(t, err)
+
+		if err = e.validateAndProcessArgs(as); err != nil {
+			return "", err
+		}
+		result, _ = as.Get("xp")
+		return result, nil
+	}
+
+	t.Run("errors", func(t *testing.T) {
+		e := getNumberEntryFromYAML(t, "example: 4\ndescription: foo\nmin: 1\nmax: 12\nunit: XP")
+
+		testData := []struct{ value, expectedError string }{
+			{"four", "Provided value is not a number: 'four'"},
+			{" ", "Provided value is not a number"},
+			{"NaN", "Provided value is not a number"},
+			{"4 gp", "Provided value is not a number: '4 gp'"},
+			{"2.5", "Provided value is not an integer number: '2.5'"},
+			{"0", "Provided value 0 is smaller than the minimum of 1"},
+			{"40", "Provided value 40 is larger than the maximum of 12"},
+		}
+
+		for _, tt := range testData {
+			t.Logf("Testing value '%v'", tt.value)
+			_, err := process(e, tt.value)
+			test.ExpectError(t, err, tt.expectedError)
+		}
+	})
+
+	t.Run("valid", func(t *testing.T) {
+		testData := []struct{ yamlInput, value, expected string }{
+			{"example: 4\ndescription: foo", " 04 ", "4"},
+			{"example: 4\ndescription: foo", "-3", "-3"},
+			{"example: 4\ndescription: foo\nunit: XP", "4XP", "4 XP"},
+			{"example: 4\ndescription: foo\nunit: XP", "4 XP", "4 XP"},
+			{"example: 4\ndescription: foo\nformat: '%03d'", "7", "007"},
+			{"example: 4\ndescription: foo\ndecimal: true", "2.50", "2.5"},
+			{"example: 4\ndescription: foo\ndecimal: true\nformat: '%.2f'\nunit: gp", "12.5", "12.50 gp"},
+		}
+
+		for _, tt := range testData {
+			t.Logf("Testing value '%v' with %v", tt.value, tt.yamlInput)
+			e := getNumberEntryFromYAML(t, tt.yamlInput)
+			result, err := process(e, tt.value)
+			test.ExpectNoError(t, err)
+			test.ExpectEqual(t, result, tt.expected)
+		}
+
+		// the plain number is additionally available
+		e := getNumberEntryFromYAML(t, "example: 4\ndescription: foo\ndecimal: true\nformat: '%.2f'\nunit: gp")
+		as, err := args.NewStore(args.StoreInit{Args: []string{"xp=012.5gp"}})
+		test.ExpectNoError(t, err)
+		test.ExpectNoError(t, e.validateAndProcessArgs(as))
+		value, _ := as.Get("xp.value")
+		test.ExpectEqual(t, value, "12.5")
+	})
+}
+
+func TestNumberEntry_AcceptedValues(t *testing.T) {
+	testData := []struct{ yamlInput, expected string }{
+		{"decimal: false", "Integer numbers"},
+		{"decimal: true", "Numbers"},
+		{"min: 1\nmax: 12", "Integer numbers between 1 and 12"},
+		{"min: 0.5\ndecimal: true", "Numbers of at least 0.5"},
+		{"max: 4\nunit: XP", "Integer numbers of at most 4, optionally followed by 'XP'"},
+	}
+
+	for _, tt := range testData {
+		e := getNumberEntryFromYAML(t, tt.yamlInput)
+		test.ExpectEqual(t, e.AcceptedValues()[0], tt.expected)
+	}
+}
+
+func TestNumberEntry_deepCopy(t *testing.T) {
+	e1 := getNumberEntryFromYAML(t, "min: 1\nmax: 12")
+	e2 := e1.deepCopy().(*numberEntry)
+	*e2.Min = 2.0
+	*e2.Max = 10.0
+	test.ExpectNotEqual(t, *e1.Min, *e2.Min)
+	test.ExpectNotEqual(t, *e1.Max, *e2.Max)
+}
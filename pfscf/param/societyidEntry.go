@@ -18,7 +18,7 @@ var (
 )
 
 type societyidEntry struct {
-	commonFields
+	commonFields `yaml:",inline"`
 
 	TheExample     string `yaml:"example"`
 	TheDescription string `yaml:"description"`
@@ -83,10 +83,10 @@ func (e *societyidEntry) describe(verbose bool) (result string) {
 	var sb strings.Builder
 
 	if !verbose {
-		fmt.Fprintf(&sb, "- %v: %v\n", e.id, e.Description())
+		fmt.Fprintf(&sb, "- %v: %v%v\n", e.id, e.Description(), UsageNote(e))
 	} else {
 		fmt.Fprintf(&sb, "- %v\n", e.id)
-		fmt.Fprintf(&sb, "\tDesc: %v\n", e.Description())
+		fmt.Fprintf(&sb, "\tDesc: %v%v\n", e.Description(), UsageNote(e))
 		fmt.Fprintf(&sb, "\tType: %v\n", e.Type())
 		fmt.Fprintf(&sb, "\tExample: %v\n", genericContentUsageExample(e.id, e.Example()))
 	}
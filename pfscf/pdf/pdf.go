@@ -2,6 +2,7 @@ package pdf
 
 import (
 	"fmt"
+	"io/ioutil"
 	"os"
 	"path/filepath"
 
@@ -166,48 +167,124 @@ func (f *File) StampIt(stampFile string, outFile string) (err error) {
 	return nil
 }
 
-// Fill is the main function used to fill a PDF file.
-func (f *File) Fill(argStore *args.Store, ct *template.Chronicle, outfile string) (err error) {
-	// prepare temporary working dir
-	workDir := utils.GetTempDir()
-	defer os.RemoveAll(workDir)
+// PreparedPage is a chronicle page that was extracted from a PDF file once and that can
+// then be filled multiple times, also concurrently.
+type PreparedPage struct {
+	workDir string
+	page    *File
+	width   float64
+	height  float64
+}
+
+// Prepare extracts the chronicle page, i.e. the last page, from the PDF file into a
+// temporary working directory. The returned object has to be closed after usage
+// so that the working directory is removed.
+func (f *File) Prepare() (pp *PreparedPage, err error) {
+	pp = &PreparedPage{workDir: utils.GetTempDir()}
+
+	if pp.page, err = f.ExtractPage(-1, pp.workDir); err != nil {
+		pp.Close()
+		return nil, err
+	}
+	pp.width, pp.height = pp.page.GetDimensionsInPoints()
+
+	return pp, nil
+}
+
+// Close removes the temporary working directory of the prepared page.
+func (pp *PreparedPage) Close() {
+	os.RemoveAll(pp.workDir)
+}
+
+// Fill fills the prepared page with the content of the provided template and arguments
+// and writes the result to the provided output file. It is safe to call this concurrently
+// for different output files.
+func (pp *PreparedPage) Fill(argStore *args.Store, ct *template.Chronicle, outfile string) (err error) {
+	stamp, err := pp.createStamp(argStore, ct, false)
+	if err != nil {
+		return err
+	}
+
+	// write stamp. Use a unique filename, as multiple stamps might be created in parallel
+	stampFile, err := ioutil.TempFile(pp.workDir, "stamp-*.pdf")
+	if err != nil {
+		return err
+	}
+	stampFile.Close()
+	defer os.Remove(stampFile.Name())
 
-	// extract chronicle page from pdf
-	extractedPage, err := f.ExtractPage(-1, workDir)
+	err = stamp.WriteToFile(stampFile.Name())
 	if err != nil {
 		return err
 	}
-	width, height := extractedPage.GetDimensionsInPoints()
 
-	// create stamp
-	stamp := stamp.NewStamp(width, height, cfg.Global.OffsetX, cfg.Global.OffsetY)
+	// add watermark/stamp to page
+	err = pp.page.StampIt(stampFile.Name(), outfile)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// DryRun performs all steps of Fill() except for writing the output file, and returns
+// the records of all elements that would have been added to the page.
+func (pp *PreparedPage) DryRun(argStore *args.Store, ct *template.Chronicle) (records []stamp.Record, err error) {
+	stamp, err := pp.createStamp(argStore, ct, true)
+	if err != nil {
+		return nil, err
+	}
+	return stamp.GetRecords(), nil
+}
+
+// createStamp creates a stamp for the prepared page and adds the content of the
+// provided template and arguments to it.
+func (pp *PreparedPage) createStamp(argStore *args.Store, ct *template.Chronicle, recording bool) (s *stamp.Stamp, err error) {
+	s = stamp.NewStamp(pp.width, pp.height, cfg.Global.OffsetX, cfg.Global.OffsetY)
+	s.SetRecording(recording)
 
 	if cfg.Global.DrawCellBorder {
-		stamp.SetCellBorder(true)
+		s.SetCellBorder(true)
 	}
 
 	// add content to stamp
-	if err = ct.GenerateOutput(stamp, argStore); err != nil {
-		return err
+	if err = ct.GenerateOutput(s, argStore); err != nil {
+		return nil, err
+	}
+	if err = s.Err(); err != nil {
+		return nil, err
 	}
 
 	if cfg.Global.DrawCanvasGrid != "" {
-		if err = stamp.DrawCanvasGrid(cfg.Global.DrawCanvasGrid); err != nil {
-			return fmt.Errorf("Error drawing canvas grid: %v", err)
+		if err = s.DrawCanvasGrid(cfg.Global.DrawCanvasGrid); err != nil {
+			return nil, fmt.Errorf("Error drawing canvas grid: %v", err)
 		}
 	}
 
-	// write stamp
-	stampFile := filepath.Join(workDir, "stamp.pdf")
-	err = stamp.WriteToFile(stampFile)
+	return s, nil
+}
+
+// Fill is the main function used to fill a PDF file.
+func (f *File) Fill(argStore *args.Store, ct *template.Chronicle, outfile string) (err error) {
+	pp, err := f.Prepare()
 	if err != nil {
 		return err
 	}
+	defer pp.Close()
 
-	// add watermark/stamp to page
-	err = extractedPage.StampIt(stampFile, outfile)
-	if err != nil {
-		return err
+	return pp.Fill(argStore, ct, outfile)
+}
+
+// MergeFiles combines the pages of all provided PDF files, in the provided order,
+// into a single PDF file.
+func MergeFiles(inFiles []string, outFile string) (err error) {
+	if len(inFiles) == 0 {
+		return fmt.Errorf("No input files provided for merging into file %v", outFile)
+	}
+
+	if err = pdfcpuapi.MergeCreateFile(inFiles, outFile, nil); err != nil {
+		os.Remove(outFile)
+		return fmt.Errorf("Error merging files into file %v: %v", outFile, err)
 	}
 
 	return nil
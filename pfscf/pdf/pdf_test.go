@@ -4,8 +4,11 @@ import (
 	"io/ioutil"
 	"os"
 	"path/filepath"
+	"sync"
 	"testing"
 
+	"github.com/Blesmol/pfscf/pfscf/args"
+	"github.com/Blesmol/pfscf/pfscf/template"
 	test "github.com/Blesmol/pfscf/pfscf/testutils"
 	"github.com/Blesmol/pfscf/pfscf/utils"
 )
@@ -114,3 +117,84 @@ func TestExtractPage(t *testing.T) {
 		}
 	})
 }
+
+func TestPreparedPage(t *testing.T) {
+	t.Run("fill in parallel", func(t *testing.T) {
+		inPdf, err := NewFile(filepath.Join(pdfTestDir, "TwoPages.pdf"))
+		test.ExpectNoError(t, err)
+
+		page, err := inPdf.Prepare()
+		test.ExpectNoError(t, err)
+		defer page.Close()
+
+		outDir := utils.GetTempDir()
+		defer os.RemoveAll(outDir)
+
+		ct := template.NewChronicleTemplate("")
+		argStore, err := args.NewStore(args.StoreInit{})
+		test.ExpectNoError(t, err)
+
+		outfiles := []string{"a.pdf", "b.pdf", "c.pdf", "d.pdf"}
+		errs := make([]error, len(outfiles))
+		var wg sync.WaitGroup
+		for idx := range outfiles {
+			wg.Add(1)
+			go func(idx int) {
+				defer wg.Done()
+				errs[idx] = page.Fill(argStore, &ct, filepath.Join(outDir, outfiles[idx]))
+			}(idx)
+		}
+		wg.Wait()
+
+		for idx, outfile := range outfiles {
+			test.ExpectNoError(t, errs[idx])
+			test.ExpectFileExists(t, filepath.Join(outDir, outfile))
+		}
+	})
+
+	t.Run("working directory is removed on close", func(t *testing.T) {
+		inPdf, err := NewFile(filepath.Join(pdfTestDir, "OnePage.pdf"))
+		test.ExpectNoError(t, err)
+
+		page, err := inPdf.Prepare()
+		test.ExpectNoError(t, err)
+		page.Close()
+
+		_, err = os.Stat(page.workDir)
+		test.ExpectTrue(t, os.IsNotExist(err))
+	})
+}
+
+func TestMergeFiles(t *testing.T) {
+	workDir := utils.GetTempDir()
+	defer os.RemoveAll(workDir)
+
+	t.Run("errors", func(t *testing.T) {
+		t.Run("no input files", func(t *testing.T) {
+			err := MergeFiles([]string{}, filepath.Join(workDir, "noInput.pdf"))
+			test.ExpectError(t, err, "No input files")
+		})
+
+		t.Run("non-existant input file", func(t *testing.T) {
+			outFile := filepath.Join(workDir, "nonExistantInput.pdf")
+			err := MergeFiles([]string{filepath.Join(pdfTestDir, "nonExistantFile.pdf")}, outFile)
+			test.ExpectError(t, err)
+		})
+	})
+
+	t.Run("valid", func(t *testing.T) {
+		outFile := filepath.Join(workDir, "merged.pdf")
+		inFiles := []string{
+			filepath.Join(pdfTestDir, "OnePage.pdf"),
+			filepath.Join(pdfTestDir, "TwoPages.pdf"),
+			filepath.Join(pdfTestDir, "OnePage.pdf"),
+		}
+
+		err := MergeFiles(inFiles, outFile)
+		test.ExpectNoError(t, err)
+
+		merged, err := NewFile(outFile)
+		test.ExpectNoError(t, err)
+		test.ExpectEqual(t, merged.numPages, 4)
+	})
+}
@@ -2,8 +2,10 @@ package preset
 
 import (
 	"fmt"
+	"sort"
 
 	"github.com/Blesmol/pfscf/pfscf/utils"
+	"github.com/Blesmol/pfscf/pfscf/yaml"
 )
 
 // Store stores a set of preset entries
@@ -108,14 +110,34 @@ func (s *Store) PresetsAreNotContradicting(IDs ...string) (err error) {
 
 // Resolve resolves inherited values between presets
 func (s *Store) Resolve() (err error) {
+	if errs := s.ResolveAll(); len(errs) > 0 {
+		return errs[0]
+	}
+	return nil
+}
+
+// ResolveAll resolves inherited values between presets. In contrast to Resolve(), it
+// does not stop at the first error but returns all errors that were found. Each error
+// is of type *yaml.PathError and carries the ID of the affected preset as path.
+func (s *Store) ResolveAll() (errs []error) {
 	resolved := make(map[string]bool)
-	for _, entryPtr := range *s {
-		if err := s.resolveInternal(entryPtr, &resolved); err != nil {
-			return err
+	for _, id := range s.getIDsSorted() {
+		if err := s.resolveInternal((*s)[id], &resolved); err != nil {
+			errs = append(errs, yaml.NewPathError(err, id))
 		}
 	}
 
-	return nil
+	return errs
+}
+
+// getIDsSorted returns the IDs of all contained presets in lexical order
+func (s *Store) getIDsSorted() (result []string) {
+	result = make([]string, 0, len(*s))
+	for id := range *s {
+		result = append(result, id)
+	}
+	sort.Strings(result)
+	return result
 }
 
 // resolveInternal recursively resolves all presets
@@ -0,0 +1,42 @@
+package stamp
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	horizontalAligns = "LCR"
+	verticalAligns   = "TMBA"
+)
+
+// parseAlign splits the provided alignment into its horizontal and vertical part.
+// Both parts are optional, but each may only be provided once. The order does not
+// matter, so "CB" and "BC" are equal.
+func parseAlign(align string) (horizontal, vertical string, err error) {
+	for _, c := range align {
+		switch {
+		case strings.ContainsRune(horizontalAligns, c):
+			if horizontal != "" {
+				return "", "", fmt.Errorf("Alignment '%v' contains more than one horizontal alignment", align)
+			}
+			horizontal = string(c)
+		case strings.ContainsRune(verticalAligns, c):
+			if vertical != "" {
+				return "", "", fmt.Errorf("Alignment '%v' contains more than one vertical alignment", align)
+			}
+			vertical = string(c)
+		default:
+			return "", "", fmt.Errorf("Unknown alignment '%c' in '%v'. Supported are horizontal alignments L, C, R and vertical alignments T, M, B, A", c, align)
+		}
+	}
+	return horizontal, vertical, nil
+}
+
+// CheckAlign checks whether the provided alignment is valid. It consists of at most one
+// horizontal alignment (L=left, C=center, R=right) and at most one vertical alignment
+// (T=top, M=middle, B=bottom, A=baseline).
+func CheckAlign(align string) (err error) {
+	_, _, err = parseAlign(align)
+	return err
+}
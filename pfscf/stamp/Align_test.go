@@ -0,0 +1,63 @@
+package stamp
+
+import (
+	"testing"
+
+	test "github.com/Blesmol/pfscf/pfscf/testutils"
+)
+
+func TestCheckAlign(t *testing.T) {
+	t.Run("errors", func(t *testing.T) {
+		test.ExpectError(t, CheckAlign("LR"), "more than one horizontal alignment")
+		test.ExpectError(t, CheckAlign("CTB"), "more than one vertical alignment")
+		test.ExpectError(t, CheckAlign("cb"), "Unknown alignment 'c' in 'cb'")
+		test.ExpectError(t, CheckAlign("CX"), "Unknown alignment 'X'")
+	})
+
+	t.Run("valid", func(t *testing.T) {
+		for _, align := range []string{"", "L", "C", "R", "T", "M", "B", "A", "CB", "BC", "LA", "RT"} {
+			t.Logf("Testing alignment '%v'", align)
+			test.ExpectNoError(t, CheckAlign(align))
+		}
+	})
+}
+
+func TestStamp_DeriveY(t *testing.T) {
+	s := NewStamp(100.0, 100.0, 0.0, 0.0)
+	s.AddCanvas("page", 0.0, 0.0, 100.0, 100.0)
+
+	for _, tt := range []struct {
+		align        string
+		y2           float64
+		expY1, expY2 float64
+	}{
+		{"CB", 80.0, 50.0, 80.0}, // y2 already set
+		{"CB", 0.0, 50.0, 40.0},
+		{"LA", 0.0, 50.0, 40.0},
+		{"RT", 0.0, 50.0, 60.0},
+		{"CM", 0.0, 45.0, 55.0},
+		{"", 0.0, 50.0, 40.0}, // above y1 without vertical alignment
+		{"R", 0.0, 50.0, 40.0},
+	} {
+		t.Logf("Testing alignment '%v' with y2=%v", tt.align, tt.y2)
+		y1, y2 := s.DeriveY("page", 50.0, tt.y2, 10.0, tt.align)
+		test.ExpectEqual(t, y1, tt.expY1)
+		test.ExpectEqual(t, y2, tt.expY2)
+	}
+}
+
+func TestStamp_RotatedText(t *testing.T) {
+	s := NewStamp(400.0, 400.0, 0.0, 0.0)
+	s.SetRecording(true)
+	s.AddCanvas("page", 0.0, 0.0, 100.0, 100.0)
+
+	// a narrow, high cell: when rotated by 90 degrees, the text runs along the height
+	s.AddTextCell("page", 0.0, 0.0, 5.0, 100.0, "Helvetica", "", 14.0, "CM", 90.0, "Some side label", true, OutputStyle{})
+	s.AddTextCell("page", 10.0, 0.0, 15.0, 100.0, "Helvetica", "", 14.0, "CM", 0.0, "Some side label", true, OutputStyle{})
+
+	records := s.GetRecords()
+	test.ExpectEqual(t, len(records), 2)
+	test.ExpectEqual(t, records[0].Fontsize, 14.0)
+	test.ExpectTrue(t, records[1].Fontsize < 14.0)
+	test.ExpectNoError(t, s.Err())
+}
@@ -0,0 +1,40 @@
+package stamp
+
+// DrawEllipse draws an ellipse on the stamp that fits exactly into the provided area.
+func (s *Stamp) DrawEllipse(canvasID string, x1Pct, y1Pct, x2Pct, y2Pct float64, os OutputStyle) {
+	if !s.isActiveCanvas(canvasID) {
+		return
+	}
+
+	xPt, yPt, wPt, hPt := s.getCanvas(canvasID).transformToAbsXYWH(x1Pct, y1Pct, x2Pct, y2Pct)
+
+	s.drawEllipseInternal(xPt+wPt/2.0, yPt+hPt/2.0, wPt/2.0, hPt/2.0, os)
+	s.addRecord("ellipse", canvasID, "", 0.0, false, false)
+}
+
+// DrawCircle draws a circle with the provided diameter on the stamp, centered on the
+// provided coordinates.
+func (s *Stamp) DrawCircle(canvasID string, xPct, yPct, sizePt float64, os OutputStyle) {
+	if !s.isActiveCanvas(canvasID) {
+		return
+	}
+
+	xCenterPt, yCenterPt := s.getCanvas(canvasID).pctToAbsPt(xPct, yPct)
+
+	s.drawEllipseInternal(xCenterPt, yCenterPt, sizePt/2.0, sizePt/2.0, os)
+	s.addRecord("circle", canvasID, "", 0.0, false, false)
+}
+
+func (s *Stamp) drawEllipseInternal(xCenterPt, yCenterPt, rxPt, ryPt float64, os OutputStyle) {
+	s.drawCellBorder(xCenterPt-rxPt, yCenterPt-ryPt, 2.0*rxPt, 2.0*ryPt)
+
+	s.pushStyle(os)
+	defer s.popStyle()
+
+	style := os.Style
+	if style == "" {
+		style = "D"
+	}
+
+	s.pdf.Ellipse(xCenterPt, yCenterPt, rxPt, ryPt, 0.0, style)
+}
@@ -0,0 +1,40 @@
+package stamp
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	test "github.com/Blesmol/pfscf/pfscf/testutils"
+	"github.com/Blesmol/pfscf/pfscf/utils"
+)
+
+func TestStamp_DrawEllipse(t *testing.T) {
+	workDir := utils.GetTempDir()
+	defer os.RemoveAll(workDir)
+
+	s := NewStamp(400.0, 400.0, 0.0, 0.0)
+	s.SetRecording(true)
+	s.AddCanvas("page", 0.0, 0.0, 100.0, 100.0)
+	s.AddCanvas("inactive", 0.0, 0.0, 0.0, 0.0) // canvases without size are inactive
+
+	outline := OutputStyle{Style: "D", DrawR: 255, Linewidth: 2.0}
+	filled := OutputStyle{Style: "F", FillR: 255, FillG: 255, Transparency: 0.5}
+
+	s.DrawEllipse("page", 10.0, 10.0, 50.0, 30.0, outline)
+	s.DrawEllipse("page", 10.0, 40.0, 50.0, 60.0, filled)
+	s.DrawCircle("page", 75.0, 25.0, 40.0, outline)
+	s.DrawCircle("page", 75.0, 50.0, 40.0, filled)
+	s.DrawCircle("inactive", 75.0, 75.0, 40.0, outline)
+
+	records := s.GetRecords()
+	test.ExpectEqual(t, len(records), 4)
+	test.ExpectEqual(t, records[0].Type, "ellipse")
+	test.ExpectEqual(t, records[1].Type, "ellipse")
+	test.ExpectEqual(t, records[2].Type, "circle")
+	test.ExpectEqual(t, records[3].Type, "circle")
+
+	outfile := filepath.Join(workDir, "ellipses.pdf")
+	test.ExpectNoError(t, s.WriteToFile(outfile))
+	test.ExpectFileExists(t, outfile)
+}
@@ -0,0 +1,223 @@
+package stamp
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/Blesmol/pfscf/pfscf/cfg"
+)
+
+const (
+	// labelFont is the Unicode font that is used for labels drawn on the stamp, e.g.
+	// for canvas names. If it is not available, labelFallbackFont is used instead.
+	labelFont         = "DejaVuSansCondensed"
+	labelFallbackFont = "Helvetica"
+)
+
+var (
+	// the core fonts that are always available, but only support the cp1252 codepage
+	coreFonts = map[string]bool{
+		"arial":        true,
+		"courier":      true,
+		"helvetica":    true,
+		"symbol":       true,
+		"times":        true,
+		"zapfdingbats": true,
+	}
+
+	fontFilesOnce sync.Once
+	fontFiles     map[string]*fontFile // lowercase font name => font file
+)
+
+// fontFile is a TrueType or OpenType font file from one of the font directories. The
+// content is only read once and then shared by all stamps.
+type fontFile struct {
+	filename string
+
+	once sync.Once
+	data []byte
+	err  error
+}
+
+// read returns the content of the font file.
+func (ff *fontFile) read() (data []byte, err error) {
+	ff.once.Do(func() {
+		ff.data, ff.err = ioutil.ReadFile(ff.filename)
+		if ff.err != nil {
+			ff.err = fmt.Errorf("Error reading font file '%v': %v", ff.filename, ff.err)
+		} else if bytes.HasPrefix(ff.data, []byte("OTTO")) {
+			// gofpdf can only embed fonts with TrueType outlines
+			ff.err = fmt.Errorf("Font file '%v' contains PostScript outlines, only OpenType fonts with TrueType outlines are supported", ff.filename)
+		}
+	})
+	return ff.data, ff.err
+}
+
+// getFontFiles returns all font files from the font directories.
+func getFontFiles() map[string]*fontFile {
+	fontFilesOnce.Do(func() {
+		fontFiles = findFontFiles(cfg.GetFontDirs()...)
+	})
+	return fontFiles
+}
+
+// findFontFiles searches the provided directories for TrueType and OpenType font files.
+// The name of a font is the filename without extension. If font files with the same
+// name exist in multiple directories, then the one from the later directory is used.
+func findFontFiles(dirs ...string) (files map[string]*fontFile) {
+	files = make(map[string]*fontFile)
+	for _, dir := range dirs {
+		entries, err := ioutil.ReadDir(dir)
+		if err != nil {
+			continue
+		}
+
+		for _, entry := range entries {
+			ext := filepath.Ext(entry.Name())
+			if entry.IsDir() || (!strings.EqualFold(ext, ".ttf") && !strings.EqualFold(ext, ".otf")) {
+				continue
+			}
+			name := strings.ToLower(strings.TrimSuffix(entry.Name(), ext))
+			files[name] = &fontFile{filename: filepath.Join(dir, entry.Name())}
+		}
+	}
+	return files
+}
+
+// getFontFile returns the font file for the provided font name.
+func getFontFile(name string) (ff *fontFile, exists bool) {
+	ff, exists = getFontFiles()[strings.ToLower(name)]
+	return ff, exists
+}
+
+// fontVariantSuffixes lists for each font variant the suffixes that are tried to find
+// the font file for that variant, e.g. "DejaVuSans-Bold" for font "DejaVuSans".
+var fontVariantSuffixes = map[string][]string{
+	"":   {""},
+	"B":  {"-Bold"},
+	"I":  {"-Italic", "-Oblique"},
+	"BI": {"-BoldItalic", "-BoldOblique"},
+}
+
+// parseFontstyle checks the provided font style, which can be any combination of
+// B (bold), I (italic) and U (underline), and returns the font variant, i.e. the
+// style without underline, in normalized form.
+func parseFontstyle(style string) (variant string, err error) {
+	style = strings.ToUpper(style)
+	for idx, c := range style {
+		if !strings.ContainsRune("BIU", c) {
+			return "", fmt.Errorf("Unknown font style '%v', only combinations of B (bold), I (italic) and U (underline) are supported", style)
+		}
+		if strings.ContainsRune(style[idx+1:], c) {
+			return "", fmt.Errorf("Font style '%v' contains '%c' more than once", style, c)
+		}
+	}
+
+	if strings.Contains(style, "B") {
+		variant += "B"
+	}
+	if strings.Contains(style, "I") {
+		variant += "I"
+	}
+	return variant, nil
+}
+
+// getFontVariantFile returns the font file for the provided font name and font variant.
+func getFontVariantFile(name, variant string) (ff *fontFile, exists bool) {
+	for _, suffix := range fontVariantSuffixes[variant] {
+		if ff, exists = getFontFile(name + suffix); exists {
+			return ff, true
+		}
+	}
+	return nil, false
+}
+
+// CheckFont checks whether the provided font can be used with the provided font style,
+// i.e. whether a font file with that name exists in one of the font directories or
+// whether it is one of the core fonts. Font files take precedence over core fonts with
+// the same name. For bold or italic text, font files need a separate file for that
+// variant, e.g. "<name>-Bold.ttf".
+func CheckFont(name, style string) (err error) {
+	variant, err := parseFontstyle(style)
+	if err != nil {
+		return err
+	}
+
+	if _, exists := getFontFile(name); exists {
+		ff, exists := getFontVariantFile(name, variant)
+		if !exists {
+			return fmt.Errorf("Font '%v' does not support font style '%v', expected a font file named %v", name, style, fontVariantNames(name, variant))
+		}
+		_, err = ff.read()
+		return err
+	}
+	if coreFonts[strings.ToLower(name)] {
+		return nil
+	}
+	return fmt.Errorf("Unknown font '%v'. Font files are searched in the following directories: %v", name, cfg.GetFontDirs())
+}
+
+// fontVariantNames returns the font names that are tried for the provided font variant.
+func fontVariantNames(name, variant string) string {
+	names := make([]string, 0)
+	for _, suffix := range fontVariantSuffixes[variant] {
+		names = append(names, fmt.Sprintf("'%v%v'", name, suffix))
+	}
+	return strings.Join(names, " or ")
+}
+
+// setFont sets the font that is used for the following text output. Returns the
+// function that has to be used to translate UTF-8 text for the selected font. In case
+// of errors the fallback font is used and the error is stored in the PDF object,
+// so that it is reported when the stamp is written.
+func (s *Stamp) setFont(name, style string, size float64) (tr func(string) string) {
+	variant, err := parseFontstyle(style)
+	if err != nil {
+		s.pdf.SetError(err)
+		style, variant = "", ""
+	}
+	s.style.Font, s.style.Fontstyle, s.style.Fontsize = name, style, size
+
+	if _, exists := getFontFile(name); !exists {
+		if !coreFonts[strings.ToLower(name)] {
+			s.pdf.SetError(CheckFont(name, style))
+			name = labelFallbackFont
+		}
+		s.pdf.SetFont(name, style, size)
+		return s.tr
+	}
+
+	ff, exists := getFontVariantFile(name, variant)
+	if !exists {
+		s.pdf.SetError(CheckFont(name, style))
+		s.pdf.SetFont(labelFallbackFont, style, size)
+		return s.tr
+	}
+
+	key := strings.ToLower(name)
+	if !s.utf8Fonts[key+variant] {
+		data, err := ff.read()
+		if err != nil {
+			s.pdf.SetError(err)
+			s.pdf.SetFont(labelFallbackFont, style, size)
+			return s.tr
+		}
+		s.pdf.AddUTF8FontFromBytes(key, variant, data)
+		s.utf8Fonts[key+variant] = true
+	}
+
+	s.pdf.SetFont(key, style, size)
+	return func(text string) string { return text }
+}
+
+// getLabelFont returns the font that should be used for labels with the provided style.
+func getLabelFont(style string) (name string) {
+	if CheckFont(labelFont, style) == nil {
+		return labelFont
+	}
+	return labelFallbackFont
+}
@@ -0,0 +1,139 @@
+package stamp
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Blesmol/pfscf/pfscf/cfg"
+	test "github.com/Blesmol/pfscf/pfscf/testutils"
+	"github.com/Blesmol/pfscf/pfscf/utils"
+)
+
+func init() {
+	utils.SetIsTestEnvironment(true)
+	cfg.SetTestingFontsDir(filepath.Join(utils.GetExecutableDir(), "..", "fonts"))
+}
+
+func TestCheckFont(t *testing.T) {
+	t.Run("errors", func(t *testing.T) {
+		err := CheckFont("NonExistantFont", "")
+		test.ExpectError(t, err, "Unknown font 'NonExistantFont'")
+	})
+
+	t.Run("valid", func(t *testing.T) {
+		for _, font := range []string{"Helvetica", "arial", "Times", "DejaVuSansCondensed", "dejavusanscondensed"} {
+			t.Logf("Testing font '%v'", font)
+			test.ExpectNoError(t, CheckFont(font, ""))
+		}
+	})
+
+	t.Run("font styles", func(t *testing.T) {
+		t.Run("errors", func(t *testing.T) {
+			test.ExpectError(t, CheckFont("Helvetica", "X"), "Unknown font style 'X'")
+			test.ExpectError(t, CheckFont("Helvetica", "BiB"), "contains 'B' more than once")
+		})
+
+		t.Run("valid", func(t *testing.T) {
+			for _, font := range []string{"Helvetica", "DejaVuSansCondensed"} {
+				for _, style := range []string{"B", "I", "U", "BI", "ib", "BIU"} {
+					t.Logf("Testing font '%v' with style '%v'", font, style)
+					test.ExpectNoError(t, CheckFont(font, style))
+				}
+			}
+		})
+	})
+}
+
+func TestParseFontstyle(t *testing.T) {
+	for _, tt := range []struct {
+		style      string
+		expVariant string
+	}{
+		{"", ""},
+		{"U", ""},
+		{"b", "B"},
+		{"UI", "I"},
+		{"IB", "BI"},
+		{"uib", "BI"},
+	} {
+		t.Logf("Testing style '%v'", tt.style)
+		variant, err := parseFontstyle(tt.style)
+		test.ExpectNoError(t, err)
+		test.ExpectEqual(t, variant, tt.expVariant)
+	}
+}
+
+func TestFindFontFiles(t *testing.T) {
+	workDir := utils.GetTempDir()
+	defer os.RemoveAll(workDir)
+
+	for filename, content := range map[string]string{
+		"TrueType.ttf":   "\x00\x01\x00\x00",
+		"OpenType.OTF":   "OTTO",
+		"NoFont.txt":     "foo",
+		"NoExtension":    "foo",
+		"Duplicated.ttf": "first",
+	} {
+		err := ioutil.WriteFile(filepath.Join(workDir, filename), []byte(content), 0644)
+		test.ExpectNoError(t, err)
+	}
+
+	otherDir := filepath.Join(workDir, "other")
+	test.ExpectNoError(t, os.Mkdir(otherDir, os.ModePerm))
+	test.ExpectNoError(t, ioutil.WriteFile(filepath.Join(otherDir, "Duplicated.ttf"), []byte("second"), 0644))
+
+	files := findFontFiles(workDir, otherDir, filepath.Join(workDir, "nonExistantDir"))
+	test.ExpectEqual(t, len(files), 3)
+
+	t.Run("TrueType font", func(t *testing.T) {
+		data, err := files["truetype"].read()
+		test.ExpectNoError(t, err)
+		test.ExpectEqual(t, len(data), 4)
+	})
+
+	t.Run("OpenType font with PostScript outlines", func(t *testing.T) {
+		_, err := files["opentype"].read()
+		test.ExpectError(t, err, "only OpenType fonts with TrueType outlines are supported")
+	})
+
+	t.Run("later directories take precedence", func(t *testing.T) {
+		data, err := files["duplicated"].read()
+		test.ExpectNoError(t, err)
+		test.ExpectEqual(t, string(data), "second")
+	})
+}
+
+func TestStamp_UnicodeText(t *testing.T) {
+	workDir := utils.GetTempDir()
+	defer os.RemoveAll(workDir)
+
+	t.Run("errors", func(t *testing.T) {
+		s := NewStamp(400.0, 400.0, 0.0, 0.0)
+		s.AddCanvas("page", 0.0, 0.0, 100.0, 100.0)
+		s.AddTextCell("page", 10.0, 10.0, 90.0, 20.0, "NonExistantFont", "", 14.0, "LB", 0.0, "foo", true, OutputStyle{})
+
+		test.ExpectError(t, s.Err(), "Unknown font")
+		test.ExpectError(t, s.WriteToFile(filepath.Join(workDir, "error.pdf")))
+	})
+
+	t.Run("valid", func(t *testing.T) {
+		s := NewStamp(400.0, 400.0, 0.0, 0.0)
+		s.AddCanvas("page", 0.0, 0.0, 100.0, 100.0)
+		for idx, text := range []string{"Zażółć gęślą jaźń", "Příliš žluťoučký kůň", "Καλημέρα κόσμε", "Grüße"} {
+			y := float64(10 * (idx + 1))
+			s.AddTextCell("page", 10.0, y, 90.0, y+5.0, "DejaVuSansCondensed", "", 14.0, "LB", 0.0, text, true, OutputStyle{})
+		}
+		s.AddTextCell("page", 10.0, 60.0, 90.0, 65.0, "Helvetica", "", 14.0, "LB", 0.0, "Grüße", true, OutputStyle{})
+		s.AddTextCell("page", 10.0, 70.0, 90.0, 75.0, "DejaVuSansCondensed", "B", 14.0, "LB", 0.0, "Grüße", true, OutputStyle{TextR: 255})
+		s.AddTextCell("page", 10.0, 80.0, 90.0, 85.0, "DejaVuSansCondensed", "BIU", 14.0, "LB", 0.0, "Grüße", true, OutputStyle{})
+		s.DrawCanvases()
+
+		test.ExpectNoError(t, s.Err())
+
+		outfile := filepath.Join(workDir, "unicode.pdf")
+		test.ExpectNoError(t, s.WriteToFile(outfile))
+		test.ExpectFileExists(t, outfile)
+	})
+}
@@ -0,0 +1,75 @@
+package stamp
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"math"
+	"net/http"
+
+	"github.com/jung-kurt/gofpdf"
+)
+
+// Supported ways to fit an image into its cell.
+const (
+	ImageFitContain = "contain" // keep aspect ratio, complete image is visible
+	ImageFitCover   = "cover"   // keep aspect ratio, complete cell is covered, image is clipped
+	ImageFitStretch = "stretch" // ignore aspect ratio, image is stretched to the cell size
+)
+
+var (
+	// supported image types, by MIME type as returned by http.DetectContentType
+	imageTypes = map[string]string{
+		"image/png":  "PNG",
+		"image/jpeg": "JPG",
+	}
+)
+
+// AddImage adds the image from the provided PNG or JPEG file to the stamp. Depending on
+// the fit, the image is scaled to fit into the cell, scaled to cover the complete cell,
+// or stretched to the size of the cell. Images that are scaled are centered in the cell.
+func (s *Stamp) AddImage(canvasID string, x1Pct, y1Pct, x2Pct, y2Pct float64, filename, fit string) (err error) {
+	if !s.isActiveCanvas(canvasID) {
+		return nil
+	}
+
+	data, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return fmt.Errorf("Error reading image file: %v", err)
+	}
+
+	imageType, supported := imageTypes[http.DetectContentType(data)]
+	if !supported {
+		return fmt.Errorf("Image file '%v' is neither a PNG nor a JPEG file", filename)
+	}
+
+	options := gofpdf.ImageOptions{ImageType: imageType}
+	info := s.pdf.RegisterImageOptionsReader(filename, options, bytes.NewReader(data))
+	if err = s.pdf.Error(); err != nil {
+		return fmt.Errorf("Error reading image file '%v': %v", filename, err)
+	}
+
+	xPt, yPt, wPt, hPt := s.getCanvas(canvasID).transformToAbsXYWH(x1Pct, y1Pct, x2Pct, y2Pct)
+
+	s.drawCellBorder(xPt, yPt, wPt, hPt)
+
+	imageW, imageH := info.Extent()
+	switch fit {
+	case ImageFitStretch:
+		s.pdf.ImageOptions(filename, xPt, yPt, wPt, hPt, false, options, 0, "")
+	case ImageFitContain, ImageFitCover:
+		scale := math.Min(wPt/imageW, hPt/imageH)
+		if fit == ImageFitCover {
+			scale = math.Max(wPt/imageW, hPt/imageH)
+			s.pdf.ClipRect(xPt, yPt, wPt, hPt, false)
+			defer s.pdf.ClipEnd()
+		}
+		imageW, imageH = imageW*scale, imageH*scale
+		s.pdf.ImageOptions(filename, xPt+(wPt-imageW)/2.0, yPt+(hPt-imageH)/2.0, imageW, imageH, false, options, 0, "")
+	default:
+		return fmt.Errorf("Unknown image fit '%v'", fit)
+	}
+
+	s.addRecord("image", canvasID, filename, 0.0, false, false)
+	return nil
+}
@@ -0,0 +1,95 @@
+package stamp
+
+import (
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	test "github.com/Blesmol/pfscf/pfscf/testutils"
+	"github.com/Blesmol/pfscf/pfscf/utils"
+)
+
+// writeTestImage writes a small image in the provided format to the provided directory.
+func writeTestImage(t *testing.T, dir, filename string) (path string) {
+	t.Helper()
+
+	img := image.NewRGBA(image.Rect(0, 0, 40, 20))
+	for x := 0; x < 40; x++ {
+		img.Set(x, x/2, color.RGBA{0, 0, 255, 255})
+	}
+
+	path = filepath.Join(dir, filename)
+	file, err := os.Create(path)
+	test.ExpectNoError(t, err)
+	defer file.Close()
+
+	if filepath.Ext(filename) == ".png" {
+		err = png.Encode(file, img)
+	} else {
+		err = jpeg.Encode(file, img, nil)
+	}
+	test.ExpectNoError(t, err)
+
+	return path
+}
+
+func TestStamp_AddImage(t *testing.T) {
+	workDir := utils.GetTempDir()
+	defer os.RemoveAll(workDir)
+
+	pngFile := writeTestImage(t, workDir, "image.png")
+	jpegFile := writeTestImage(t, workDir, "image.jpg")
+
+	newTestStamp := func() (s *Stamp) {
+		s = NewStamp(400.0, 400.0, 0.0, 0.0)
+		s.SetRecording(true)
+		s.AddCanvas("page", 0.0, 0.0, 100.0, 100.0)
+		return s
+	}
+
+	t.Run("errors", func(t *testing.T) {
+		t.Run("missing file", func(t *testing.T) {
+			s := newTestStamp()
+			err := s.AddImage("page", 10.0, 10.0, 50.0, 50.0, filepath.Join(workDir, "nonExistant.png"), ImageFitContain)
+			test.ExpectError(t, err, "Error reading image file")
+		})
+
+		t.Run("unsupported file type", func(t *testing.T) {
+			textFile := filepath.Join(workDir, "noImage.png")
+			test.ExpectNoError(t, ioutil.WriteFile(textFile, []byte("no image"), 0644))
+
+			s := newTestStamp()
+			err := s.AddImage("page", 10.0, 10.0, 50.0, 50.0, textFile, ImageFitContain)
+			test.ExpectError(t, err, "is neither a PNG nor a JPEG file")
+		})
+
+		t.Run("unknown fit", func(t *testing.T) {
+			s := newTestStamp()
+			err := s.AddImage("page", 10.0, 10.0, 50.0, 50.0, pngFile, "foo")
+			test.ExpectError(t, err, "Unknown image fit 'foo'")
+		})
+	})
+
+	t.Run("valid", func(t *testing.T) {
+		s := newTestStamp()
+		for idx, fit := range []string{ImageFitContain, ImageFitCover, ImageFitStretch} {
+			y := float64(idx * 30)
+			test.ExpectNoError(t, s.AddImage("page", 10.0, y, 40.0, y+25.0, pngFile, fit))
+			test.ExpectNoError(t, s.AddImage("page", 50.0, y, 90.0, y+25.0, jpegFile, fit))
+		}
+
+		records := s.GetRecords()
+		test.ExpectEqual(t, len(records), 6)
+		test.ExpectEqual(t, records[0].Type, "image")
+		test.ExpectEqual(t, records[0].Value, pngFile)
+
+		outfile := filepath.Join(workDir, "images.pdf")
+		test.ExpectNoError(t, s.WriteToFile(outfile))
+		test.ExpectFileExists(t, outfile)
+	})
+}
@@ -0,0 +1,63 @@
+package stamp
+
+import (
+	"fmt"
+)
+
+// Supported glyphs for marks.
+const (
+	MarkCross  = "cross"  // an X, like a strikeout
+	MarkCheck  = "check"  // a check mark
+	MarkCircle = "circle" // a filled circle
+	MarkSquare = "square" // a filled square
+)
+
+// DrawMark draws a mark with the provided glyph on the stamp, e.g. to tick a checkbox.
+// The mark is centered on the provided coordinates and is as wide and high as the
+// provided size. Outlined glyphs use the draw color and linewidth of the provided
+// output style, filled glyphs the fill color.
+func (s *Stamp) DrawMark(canvasID string, xPct, yPct, sizePt float64, glyph string, os OutputStyle) (err error) {
+	if !s.isActiveCanvas(canvasID) {
+		return nil
+	}
+
+	xCenterPt, yCenterPt := s.getCanvas(canvasID).pctToAbsPt(xPct, yPct)
+	halfSize := sizePt * 0.5
+	x1Pt, y1Pt := xCenterPt-halfSize, yCenterPt-halfSize
+
+	switch glyph {
+	case MarkCross:
+		s.drawStrikeoutInternal(x1Pt, y1Pt, xCenterPt+halfSize, yCenterPt+halfSize, os)
+	case MarkCheck, MarkCircle, MarkSquare:
+		s.drawMarkInternal(x1Pt, y1Pt, sizePt, glyph, os)
+	default:
+		return fmt.Errorf("Unknown glyph '%v'", glyph)
+	}
+
+	s.addRecord("mark", canvasID, glyph, 0.0, false, false)
+	return nil
+}
+
+func (s *Stamp) drawMarkInternal(x1Pt, y1Pt, sizePt float64, glyph string, os OutputStyle) {
+	s.drawCellBorder(x1Pt, y1Pt, sizePt, sizePt)
+
+	style := os
+	if glyph == MarkCheck {
+		// round caps and joins look more like a hand-drawn tick
+		style.LineCap, style.LineJoin = "round", "round"
+	}
+	s.pushStyle(style)
+	defer s.popStyle()
+
+	switch glyph {
+	case MarkCheck:
+		s.pdf.MoveTo(x1Pt+0.1*sizePt, y1Pt+0.55*sizePt)
+		s.pdf.LineTo(x1Pt+0.4*sizePt, y1Pt+0.85*sizePt)
+		s.pdf.LineTo(x1Pt+0.9*sizePt, y1Pt+0.15*sizePt)
+		s.pdf.DrawPath("D")
+	case MarkCircle:
+		s.pdf.Circle(x1Pt+sizePt/2.0, y1Pt+sizePt/2.0, sizePt/2.0, "F")
+	case MarkSquare:
+		s.pdf.Rect(x1Pt, y1Pt, sizePt, sizePt, "F")
+	}
+}
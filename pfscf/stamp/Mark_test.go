@@ -0,0 +1,50 @@
+package stamp
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	test "github.com/Blesmol/pfscf/pfscf/testutils"
+	"github.com/Blesmol/pfscf/pfscf/utils"
+)
+
+func TestStamp_DrawMark(t *testing.T) {
+	workDir := utils.GetTempDir()
+	defer os.RemoveAll(workDir)
+
+	style := OutputStyle{DrawR: 0, DrawG: 0, DrawB: 255, FillR: 0, FillG: 0, FillB: 255, Linewidth: 1.5}
+
+	newTestStamp := func() (s *Stamp) {
+		s = NewStamp(400.0, 400.0, 0.0, 0.0)
+		s.SetRecording(true)
+		s.AddCanvas("page", 0.0, 0.0, 100.0, 100.0)
+		return s
+	}
+
+	t.Run("errors", func(t *testing.T) {
+		s := newTestStamp()
+		err := s.DrawMark("page", 50.0, 50.0, 10.0, "star", style)
+		test.ExpectError(t, err, "Unknown glyph 'star'")
+		test.ExpectEqual(t, len(s.GetRecords()), 0)
+	})
+
+	t.Run("valid", func(t *testing.T) {
+		s := newTestStamp()
+		glyphs := []string{MarkCross, MarkCheck, MarkCircle, MarkSquare}
+		for idx, glyph := range glyphs {
+			test.ExpectNoError(t, s.DrawMark("page", 20.0*float64(idx+1), 50.0, 12.0, glyph, style))
+		}
+
+		records := s.GetRecords()
+		test.ExpectEqual(t, len(records), len(glyphs))
+		for idx, glyph := range glyphs {
+			test.ExpectEqual(t, records[idx].Type, "mark")
+			test.ExpectEqual(t, records[idx].Value, glyph)
+		}
+
+		outfile := filepath.Join(workDir, "marks.pdf")
+		test.ExpectNoError(t, s.WriteToFile(outfile))
+		test.ExpectFileExists(t, outfile)
+	})
+}
@@ -1,63 +1,159 @@
 package stamp
 
-import "github.com/jung-kurt/gofpdf"
+import (
+	"github.com/jung-kurt/gofpdf"
 
-// OutputStyle allows to provide only the required drawing parameters
+	"github.com/Blesmol/pfscf/pfscf/utils"
+)
+
+const (
+	defaultBlendMode = "Normal"
+	defaultLineCap   = "butt"
+	defaultLineJoin  = "miter"
+)
+
+// OutputStyle describes all parameters that are used when drawing something on the stamp.
+// Zero values for Linewidth, Font and Fontsize mean that the current value is kept, empty
+// values for BlendMode, LineCap and LineJoin select the PDF defaults.
 type OutputStyle struct {
 	Style               string // F=filled, D=outline, FD=both, default=D
 	FillR, FillG, FillB int
 	DrawR, DrawG, DrawB int
 	TextR, TextG, TextB int
-	Transparency        float64
+	Transparency        float64 // 0.0=opaque, 1.0=invisible
 	BlendMode           string
 	Linewidth           float64
+	DashPattern         []float64 // alternating lengths of dashes and gaps, empty for solid lines
+	DashPhase           float64
+	LineCap             string // butt, round or square
+	LineJoin            string // miter, round or bevel
+	Font                string
+	Fontstyle           string
 	Fontsize            float64
 	CellMargin          float64
 }
 
+// getOutputStyle returns the style that gofpdf uses after its initialization. Dash
+// pattern, line cap and line join cannot be retrieved and are set to the PDF defaults.
+// No font is set initially.
 func getOutputStyle(p *gofpdf.Fpdf) (s OutputStyle) {
-	// s.Style cannot be retrieved, as this is a parameter set for the concrete call. Nevertheless, it may be used elsewhere
-	// Font cannot be retrieved, no proper API provided
+	var alpha float64
 
 	s.FillR, s.FillG, s.FillB = p.GetFillColor()
 	s.DrawR, s.DrawG, s.DrawB = p.GetDrawColor()
 	s.TextR, s.TextG, s.TextB = p.GetTextColor()
-	s.Transparency, s.BlendMode = p.GetAlpha()
+	alpha, s.BlendMode = p.GetAlpha()
+	s.Transparency = 1.0 - alpha
 	s.Linewidth = p.GetLineWidth()
-	s.Fontsize, _ = p.GetFontSize()
+	s.LineCap = defaultLineCap
+	s.LineJoin = defaultLineJoin
 	s.CellMargin = p.GetCellMargin()
 
 	return s
 }
 
-// setOutputStyle sets all pdf parameters that are not equal to the desired values.
-// TODO this currently sets *everything* in here... even if the values are currently not set.
-func (s OutputStyle) setOutputStyle(p *gofpdf.Fpdf) {
-	if r, g, b := p.GetFillColor(); s.FillR != r || s.FillG != g || s.FillB != b {
-		p.SetFillColor(s.FillR, s.FillG, s.FillB)
+// normalized returns a copy of the style where all values that should be taken over from
+// the provided current style or from the defaults are filled in.
+func (os OutputStyle) normalized(current OutputStyle) OutputStyle {
+	if !utils.IsSet(os.BlendMode) {
+		os.BlendMode = defaultBlendMode
+	}
+	if !utils.IsSet(os.Linewidth) {
+		os.Linewidth = current.Linewidth
+	}
+	if !utils.IsSet(os.LineCap) {
+		os.LineCap = defaultLineCap
+	}
+	if !utils.IsSet(os.LineJoin) {
+		os.LineJoin = defaultLineJoin
+	}
+	if !utils.IsSet(os.Font) {
+		os.Font, os.Fontstyle = current.Font, current.Fontstyle
+	}
+	if !utils.IsSet(os.Fontsize) {
+		os.Fontsize = current.Fontsize
 	}
+	return os
+}
 
-	if r, g, b := p.GetDrawColor(); s.DrawR != r || s.DrawG != g || s.DrawB != b {
-		p.SetDrawColor(s.DrawR, s.DrawG, s.DrawB)
+func equalDashPatterns(a, b []float64) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for idx := range a {
+		if a[idx] != b[idx] {
+			return false
+		}
 	}
+	return true
+}
 
-	if r, g, b := p.GetTextColor(); s.TextR != r || s.TextG != g || s.TextB != b {
-		p.SetTextColor(s.TextR, s.TextG, s.TextB)
+// pushStyle applies the provided output style to the stamp. The style that was active
+// before is kept on a stack and becomes active again with the matching call to popStyle().
+func (s *Stamp) pushStyle(os OutputStyle) {
+	s.styleStack = append(s.styleStack, s.style)
+	s.applyStyle(os)
+}
+
+// popStyle restores the style that was active before the last call to pushStyle().
+func (s *Stamp) popStyle() {
+	utils.Assert(len(s.styleStack) > 0, "popStyle() requires a matching call to pushStyle()")
+
+	last := len(s.styleStack) - 1
+	previous := s.styleStack[last]
+	s.styleStack = s.styleStack[:last]
+	s.applyStyle(previous)
+}
+
+// applyStyle sets all pdf parameters of the provided style that differ from the currently
+// active style.
+func (s *Stamp) applyStyle(os OutputStyle) {
+	cur := s.style
+	os = os.normalized(cur)
+
+	if os.FillR != cur.FillR || os.FillG != cur.FillG || os.FillB != cur.FillB {
+		s.pdf.SetFillColor(os.FillR, os.FillG, os.FillB)
+	}
+
+	if os.DrawR != cur.DrawR || os.DrawG != cur.DrawG || os.DrawB != cur.DrawB {
+		s.pdf.SetDrawColor(os.DrawR, os.DrawG, os.DrawB)
 	}
 
-	if tr, blend := p.GetAlpha(); tr != s.Transparency || blend != s.BlendMode {
-		p.SetAlpha(s.Transparency, s.BlendMode)
+	if os.TextR != cur.TextR || os.TextG != cur.TextG || os.TextB != cur.TextB {
+		s.pdf.SetTextColor(os.TextR, os.TextG, os.TextB)
 	}
 
-	if s.Linewidth != p.GetLineWidth() {
-		p.SetLineWidth(s.Linewidth)
+	if os.Transparency != cur.Transparency || os.BlendMode != cur.BlendMode {
+		s.pdf.SetAlpha(1.0-os.Transparency, os.BlendMode)
 	}
 
-	if fs, _ := p.GetFontSize(); fs != s.Fontsize {
-		p.SetFontSize(fs)
+	if os.Linewidth != cur.Linewidth {
+		s.pdf.SetLineWidth(os.Linewidth)
 	}
 
-	if s.CellMargin != p.GetCellMargin() {
-		p.SetCellMargin(s.CellMargin)
+	if !equalDashPatterns(os.DashPattern, cur.DashPattern) || os.DashPhase != cur.DashPhase {
+		s.pdf.SetDashPattern(os.DashPattern, os.DashPhase)
 	}
+
+	if os.LineCap != cur.LineCap {
+		s.pdf.SetLineCapStyle(os.LineCap)
+	}
+
+	if os.LineJoin != cur.LineJoin {
+		s.pdf.SetLineJoinStyle(os.LineJoin)
+	}
+
+	if os.CellMargin != cur.CellMargin {
+		s.pdf.SetCellMargin(os.CellMargin)
+	}
+
+	if os.Font != cur.Font || os.Fontstyle != cur.Fontstyle || os.Fontsize != cur.Fontsize {
+		if utils.IsSet(os.Font) {
+			s.setFont(os.Font, os.Fontstyle, os.Fontsize)
+		} else {
+			s.pdf.SetFontSize(os.Fontsize)
+		}
+	}
+
+	s.style = os
 }
@@ -0,0 +1,74 @@
+package stamp
+
+import (
+	"testing"
+
+	test "github.com/Blesmol/pfscf/pfscf/testutils"
+)
+
+func TestStamp_pushPopStyle(t *testing.T) {
+	newTestStamp := func() (s *Stamp) {
+		s = NewStamp(400.0, 400.0, 0.0, 0.0)
+		s.AddCanvas("page", 0.0, 0.0, 100.0, 100.0)
+		return s
+	}
+
+	t.Run("colors and lines", func(t *testing.T) {
+		s := newTestStamp()
+		initial := s.style
+
+		s.pushStyle(OutputStyle{DrawR: 10, DrawG: 20, DrawB: 30, FillR: 40, FillG: 50, FillB: 60, Linewidth: 3.0, Transparency: 0.25, DashPattern: []float64{2.0, 1.0}, LineCap: "round"})
+		r, g, b := s.pdf.GetDrawColor()
+		test.ExpectEqual(t, r, 10)
+		test.ExpectEqual(t, g, 20)
+		test.ExpectEqual(t, b, 30)
+		r, g, b = s.pdf.GetFillColor()
+		test.ExpectEqual(t, r, 40)
+		test.ExpectEqual(t, g, 50)
+		test.ExpectEqual(t, b, 60)
+		test.ExpectEqual(t, s.pdf.GetLineWidth(), 3.0)
+		alpha, blendMode := s.pdf.GetAlpha()
+		test.ExpectEqual(t, alpha, 0.75)
+		test.ExpectEqual(t, blendMode, defaultBlendMode)
+		test.ExpectEqual(t, len(s.style.DashPattern), 2)
+		test.ExpectEqual(t, s.style.LineCap, "round")
+		test.ExpectEqual(t, s.style.LineJoin, defaultLineJoin)
+
+		s.popStyle()
+		r, g, b = s.pdf.GetDrawColor()
+		test.ExpectEqual(t, r, initial.DrawR)
+		test.ExpectEqual(t, g, initial.DrawG)
+		test.ExpectEqual(t, b, initial.DrawB)
+		test.ExpectEqual(t, s.pdf.GetLineWidth(), initial.Linewidth)
+		alpha, _ = s.pdf.GetAlpha()
+		test.ExpectEqual(t, alpha, 1.0)
+		test.ExpectEqual(t, len(s.style.DashPattern), 0)
+		test.ExpectEqual(t, s.style.LineCap, defaultLineCap)
+		test.ExpectEqual(t, len(s.styleStack), 0)
+	})
+
+	t.Run("unset linewidth keeps current value", func(t *testing.T) {
+		s := newTestStamp()
+
+		s.pushStyle(OutputStyle{Linewidth: 2.0})
+		s.pushStyle(OutputStyle{DrawR: 255})
+		test.ExpectEqual(t, s.pdf.GetLineWidth(), 2.0)
+		s.popStyle()
+		s.popStyle()
+	})
+
+	t.Run("font size is restored after text cell", func(t *testing.T) {
+		s := newTestStamp()
+		s.setFont("Helvetica", "", 10.0)
+
+		s.AddTextCell("page", 10.0, 10.0, 90.0, 20.0, "Helvetica", "B", 14.0, "CB", 0.0, "foo", false, OutputStyle{TextR: 255})
+		s.AddTextCell("page", 10.0, 30.0, 20.0, 90.0, "Helvetica", "", 16.0, "CB", 90.0, "bar", false, OutputStyle{})
+
+		ptSize, _ := s.pdf.GetFontSize()
+		test.ExpectEqual(t, ptSize, 10.0)
+		test.ExpectEqual(t, s.style.Fontsize, 10.0)
+		test.ExpectEqual(t, s.style.Fontstyle, "")
+		r, _, _ := s.pdf.GetTextColor()
+		test.ExpectEqual(t, r, 0)
+	})
+}
@@ -0,0 +1,143 @@
+package stamp
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+const (
+	// ellipsis is appended to paragraphs that had to be truncated
+	ellipsis = "…"
+)
+
+// AddParagraphCell adds a paragraph to the stamp. The text is wrapped at word boundaries
+// so that it fits into the width of the cell, explicit line breaks are kept. The
+// lineheight is provided as factor of the fontsize, and maxLines limits the number of
+// lines in addition to the cell height, 0 means no limit.
+//
+// If the text does not fit into the cell, the fontsize is reduced until it fits or until
+// the minimum fontsize is reached. If it does not fit even then, the text is truncated and
+// ends with an ellipsis if truncate is set, else an error is returned. The text color and
+// transparency are taken from the provided output style.
+func (s *Stamp) AddParagraphCell(canvasID string, x1Pct, y1Pct, x2Pct, y2Pct float64, font, fontstyle string, fontsize, lineheight float64, maxLines int, align, text string, truncate bool, os OutputStyle) (err error) {
+	if !s.isActiveCanvas(canvasID) {
+		return nil
+	}
+
+	style := os
+	style.CellMargin = 0
+	s.pushStyle(style)
+	defer s.popStyle()
+
+	xPt, yPt, wPt, hPt := s.getCanvas(canvasID).transformToAbsXYWH(x1Pct, y1Pct, x2Pct, y2Pct)
+
+	// number of lines that fit into the cell for the given fontsize
+	availableLines := func(fontsize float64) (lines int) {
+		lines = int(math.Floor(hPt/(fontsize*lineheight) + 0.0001))
+		if maxLines > 0 && maxLines < lines {
+			lines = maxLines
+		}
+		if lines < 1 {
+			lines = 1
+		}
+		return lines
+	}
+
+	var lines []string
+	var tr func(string) string
+	effectiveFontsize := math.Max(fontsize, minFontSize)
+	for ; effectiveFontsize >= minFontSize; effectiveFontsize -= 0.25 {
+		tr = s.setFont(font, fontstyle, effectiveFontsize)
+		lines = s.wrapText(tr, text, wPt)
+		if len(lines) <= availableLines(effectiveFontsize) {
+			break
+		}
+	}
+
+	truncated := false
+	if effectiveFontsize < minFontSize {
+		effectiveFontsize = minFontSize
+		tr = s.setFont(font, fontstyle, effectiveFontsize)
+		lines = s.wrapText(tr, text, wPt)
+
+		if numLines := availableLines(effectiveFontsize); len(lines) > numLines {
+			if !truncate {
+				return fmt.Errorf("Text does not fit into the paragraph even with minimum font size %v: %v lines required, but only %v available", minFontSize, len(lines), numLines)
+			}
+			lines = lines[:numLines]
+			lines[numLines-1] = s.addEllipsis(tr, lines[numLines-1], wPt)
+			truncated = true
+		}
+	}
+
+	s.drawCellBorder(xPt, yPt, wPt, hPt)
+
+	lhPt := effectiveFontsize * lineheight
+	for idx, line := range lines {
+		s.pdf.SetXY(xPt, yPt+float64(idx)*lhPt)
+		s.pdf.CellFormat(wPt, lhPt, tr(line), "0", 0, align+"M", false, 0, "")
+	}
+
+	s.addRecord("paragraph", canvasID, text, effectiveFontsize, effectiveFontsize < fontsize && effectiveFontsize <= minFontSize, truncated)
+	return nil
+}
+
+// CheckParagraphFontsize checks whether the provided fontsize can be used for paragraphs,
+// i.e. whether it is not smaller than the minimum fontsize.
+func CheckParagraphFontsize(fontsize float64) (err error) {
+	if fontsize < minFontSize {
+		return fmt.Errorf("Fontsize %v is smaller than the minimum font size %v", fontsize, minFontSize)
+	}
+	return nil
+}
+
+// wrapText splits the provided text into lines that fit into the provided width with the
+// current font. Lines are broken at whitespace, words that are longer than a complete
+// line are broken at the last character that fits.
+func (s *Stamp) wrapText(tr func(string) string, text string, widthPt float64) (lines []string) {
+	fits := func(line string) bool {
+		return s.pdf.GetStringWidth(tr(line)) <= widthPt
+	}
+
+	for _, paragraph := range strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n") {
+		current := ""
+		for _, word := range strings.Fields(paragraph) {
+			if current != "" && fits(current+" "+word) {
+				current += " " + word
+				continue
+			}
+			if current != "" {
+				lines = append(lines, current)
+			}
+
+			// break up words that do not even fit into an empty line
+			runes := []rune(word)
+			for len(runes) > 1 && !fits(string(runes)) {
+				end := len(runes) - 1
+				for end > 1 && !fits(string(runes[:end])) {
+					end--
+				}
+				lines = append(lines, string(runes[:end]))
+				runes = runes[end:]
+			}
+			current = string(runes)
+		}
+		lines = append(lines, current)
+	}
+
+	return lines
+}
+
+// addEllipsis appends an ellipsis to the provided line. Characters at the end of the
+// line are removed until the result fits into the provided width.
+func (s *Stamp) addEllipsis(tr func(string) string, line string, widthPt float64) (result string) {
+	runes := []rune(strings.TrimRight(line, " "))
+	for {
+		result = strings.TrimRight(string(runes), " ") + ellipsis
+		if len(runes) == 0 || s.pdf.GetStringWidth(tr(result)) <= widthPt {
+			return result
+		}
+		runes = runes[:len(runes)-1]
+	}
+}
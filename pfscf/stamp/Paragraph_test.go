@@ -0,0 +1,129 @@
+package stamp
+
+import (
+	"strings"
+	"testing"
+
+	test "github.com/Blesmol/pfscf/pfscf/testutils"
+)
+
+const (
+	paragraphTestText = "Lorem ipsum dolor sit amet, consectetur adipiscing elit, sed do eiusmod tempor incididunt ut labore et dolore magna aliqua."
+)
+
+func TestStamp_WrapText(t *testing.T) {
+	s := NewStamp(400.0, 400.0, 0.0, 0.0)
+	tr := s.setFont("Helvetica", "", 10.0)
+
+	t.Run("wrap at whitespace", func(t *testing.T) {
+		lines := s.wrapText(tr, paragraphTestText, 100.0)
+		test.ExpectTrue(t, len(lines) > 1)
+		test.ExpectEqual(t, strings.Join(lines, " "), paragraphTestText)
+		for _, line := range lines {
+			test.ExpectTrue(t, s.pdf.GetStringWidth(line) <= 100.0)
+		}
+	})
+
+	t.Run("keep line breaks", func(t *testing.T) {
+		lines := s.wrapText(tr, "foo\r\n\nbar", 100.0)
+		test.ExpectEqual(t, len(lines), 3)
+		test.ExpectEqual(t, lines[0], "foo")
+		test.ExpectEqual(t, lines[1], "")
+		test.ExpectEqual(t, lines[2], "bar")
+	})
+
+	t.Run("break long words", func(t *testing.T) {
+		word := strings.Repeat("x", 50)
+		lines := s.wrapText(tr, word, 50.0)
+		test.ExpectTrue(t, len(lines) > 1)
+		test.ExpectEqual(t, strings.Join(lines, ""), word)
+	})
+}
+
+func TestStamp_AddParagraphCell(t *testing.T) {
+	newTestStamp := func() (s *Stamp) {
+		s = NewStamp(400.0, 400.0, 0.0, 0.0)
+		s.SetRecording(true)
+		s.AddCanvas("page", 0.0, 0.0, 100.0, 100.0)
+		return s
+	}
+
+	t.Run("errors", func(t *testing.T) {
+		s := newTestStamp()
+		err := s.AddParagraphCell("page", 0.0, 0.0, 10.0, 2.0, "Helvetica", "", 14.0, 1.2, 0, "L", paragraphTestText, false, OutputStyle{})
+		test.ExpectError(t, err, "Text does not fit into the paragraph")
+		test.ExpectEqual(t, len(s.GetRecords()), 0)
+	})
+
+	t.Run("valid", func(t *testing.T) {
+		t.Run("fits with provided fontsize", func(t *testing.T) {
+			s := newTestStamp()
+			err := s.AddParagraphCell("page", 0.0, 0.0, 100.0, 50.0, "Helvetica", "B", 14.0, 1.2, 0, "L", paragraphTestText, false, OutputStyle{TextR: 255})
+			test.ExpectNoError(t, err)
+
+			records := s.GetRecords()
+			test.ExpectEqual(t, len(records), 1)
+			test.ExpectEqual(t, records[0].Type, "paragraph")
+			test.ExpectEqual(t, records[0].Fontsize, 14.0)
+			test.ExpectFalse(t, records[0].Shrunk)
+			test.ExpectFalse(t, records[0].Truncated)
+		})
+
+		t.Run("shrink to fit", func(t *testing.T) {
+			s := newTestStamp()
+			err := s.AddParagraphCell("page", 0.0, 0.0, 50.0, 10.0, "Helvetica", "", 14.0, 1.2, 0, "L", paragraphTestText, false, OutputStyle{})
+			test.ExpectNoError(t, err)
+
+			records := s.GetRecords()
+			test.ExpectTrue(t, records[0].Fontsize < 14.0)
+			test.ExpectFalse(t, records[0].Truncated)
+		})
+
+		t.Run("maximum number of lines", func(t *testing.T) {
+			s := newTestStamp()
+			err := s.AddParagraphCell("page", 0.0, 0.0, 50.0, 50.0, "Helvetica", "", 14.0, 1.2, 1, "L", paragraphTestText, true, OutputStyle{})
+			test.ExpectNoError(t, err)
+
+			records := s.GetRecords()
+			test.ExpectEqual(t, records[0].Fontsize, minFontSize)
+			test.ExpectTrue(t, records[0].Truncated)
+		})
+
+		t.Run("short text with small fontsize", func(t *testing.T) {
+			for _, truncate := range []bool{true, false} {
+				s := newTestStamp()
+				err := s.AddParagraphCell("page", 0.0, 0.0, 50.0, 50.0, "Helvetica", "", 3.0, 1.2, 0, "L", "short", truncate, OutputStyle{})
+				test.ExpectNoError(t, err)
+
+				records := s.GetRecords()
+				test.ExpectEqual(t, records[0].Fontsize, minFontSize)
+				test.ExpectFalse(t, records[0].Shrunk)
+				test.ExpectFalse(t, records[0].Truncated)
+			}
+		})
+
+		t.Run("truncate with ellipsis", func(t *testing.T) {
+			s := newTestStamp()
+			err := s.AddParagraphCell("page", 0.0, 0.0, 10.0, 2.0, "DejaVuSansCondensed", "", 14.0, 1.2, 0, "R", paragraphTestText, true, OutputStyle{})
+			test.ExpectNoError(t, err)
+			test.ExpectNoError(t, s.Err())
+
+			records := s.GetRecords()
+			test.ExpectEqual(t, records[0].Fontsize, minFontSize)
+			test.ExpectTrue(t, records[0].Shrunk)
+			test.ExpectTrue(t, records[0].Truncated)
+		})
+	})
+}
+
+func TestStamp_AddEllipsis(t *testing.T) {
+	s := NewStamp(400.0, 400.0, 0.0, 0.0)
+	tr := s.setFont("Helvetica", "", 10.0)
+
+	test.ExpectEqual(t, s.addEllipsis(tr, "foo", 100.0), "foo…")
+	test.ExpectEqual(t, s.addEllipsis(tr, "foo bar ", 100.0), "foo bar…")
+
+	result := s.addEllipsis(tr, strings.Repeat("x", 50), 30.0)
+	test.ExpectTrue(t, strings.HasSuffix(result, "…"))
+	test.ExpectTrue(t, s.pdf.GetStringWidth(tr(result)) <= 30.0)
+}
@@ -0,0 +1,59 @@
+package stamp
+
+import (
+	"strings"
+)
+
+// Record describes a single element that was added to a stamp. Records are only
+// collected if recording was enabled for the stamp, e.g. to preview the output
+// without writing a PDF file.
+type Record struct {
+	Content   string  // path of the content entry that added the element, e.g. "3" or "3/0"
+	Type      string  // type of the element, e.g. "text" or "line"
+	Canvas    string  // ID of the canvas on which the element was added
+	Value     string  // text of the element, empty for drawings
+	Fontsize  float64 // effective fontsize of the text, 0 for drawings
+	Shrunk    bool    // text had to be shrunk to the minimum font size
+	Truncated bool    // text did not fit even with the minimum font size and was truncated
+}
+
+// SetRecording sets whether the stamp should keep a record of all added elements.
+func (s *Stamp) SetRecording(shouldRecord bool) {
+	s.recording = shouldRecord
+}
+
+// GetRecords returns the records of all elements that were added to the stamp
+// while recording was enabled.
+func (s *Stamp) GetRecords() (records []Record) {
+	return s.records
+}
+
+// EnterContent marks that the following elements are added by the content entry with
+// the provided ID. Content entries can be nested, so each call has to be followed by a
+// matching call to LeaveContent().
+func (s *Stamp) EnterContent(id string) {
+	s.contentPath = append(s.contentPath, id)
+}
+
+// LeaveContent marks that the current content entry has added all its elements.
+func (s *Stamp) LeaveContent() {
+	if len(s.contentPath) > 0 {
+		s.contentPath = s.contentPath[:len(s.contentPath)-1]
+	}
+}
+
+func (s *Stamp) addRecord(elementType, canvasID, value string, fontsize float64, shrunk, truncated bool) {
+	if !s.recording {
+		return
+	}
+
+	s.records = append(s.records, Record{
+		Content:   strings.Join(s.contentPath, "/"),
+		Type:      elementType,
+		Canvas:    canvasID,
+		Value:     value,
+		Fontsize:  fontsize,
+		Shrunk:    shrunk,
+		Truncated: truncated,
+	})
+}
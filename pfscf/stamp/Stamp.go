@@ -2,6 +2,7 @@ package stamp
 
 import (
 	"fmt"
+	"math"
 	"strconv"
 
 	"github.com/jung-kurt/gofpdf"
@@ -13,6 +14,10 @@ const (
 	minFontSize = 4.0
 )
 
+var (
+	cellBorderStyle = OutputStyle{Linewidth: 0.5} // black
+)
+
 // Stamp is a wraper for a PDF page
 type Stamp struct {
 	pdf *gofpdf.Fpdf
@@ -25,6 +30,15 @@ type Stamp struct {
 	offsetX     float64
 	offsetY     float64
 
+	utf8Fonts map[string]bool // UTF-8 fonts that were already added to the PDF
+
+	style      OutputStyle   // currently active style, as gofpdf does not provide all of it
+	styleStack []OutputStyle // styles that were active before, see pushStyle()
+
+	recording   bool
+	records     []Record
+	contentPath []string
+
 	tr func(string) string // translator function from UTF-8 to specific codepage
 }
 
@@ -39,6 +53,7 @@ func NewStamp(dimX, dimY, offsetX, offsetY float64) (s *Stamp) {
 	s.offsetY = offsetY
 
 	s.canvasStore = make(map[string]canvas, 0)
+	s.utf8Fonts = make(map[string]bool)
 	s.SetPageCanvas(0.0, 0.0, 100.0, 100.0)
 
 	s.SetCellBorder(false)
@@ -50,6 +65,7 @@ func NewStamp(dimX, dimY, offsetX, offsetY float64) (s *Stamp) {
 	s.pdf.SetMargins(0, 0, 0)
 	s.pdf.SetAutoPageBreak(false, 0)
 	s.pdf.AddPage() // 0,0 is top-left. To change use AddPageFormat() instead
+	s.style = getOutputStyle(s.pdf)
 
 	s.tr = s.pdf.UnicodeTranslatorFromDescriptor("") // default codepage here is cp1252
 
@@ -105,18 +121,33 @@ func (s *Stamp) isActiveCanvas(id string) bool {
 	return c.isActive
 }
 
-func (s *Stamp) saveCurrentOutputStyle() (style OutputStyle) {
-	return getOutputStyle(s.pdf)
+// drawCellBorder draws a border around the provided area if cell borders should be drawn.
+func (s *Stamp) drawCellBorder(xPt, yPt, wPt, hPt float64) {
+	if !s.shouldDrawCellBorder() {
+		return
+	}
+
+	s.pushStyle(cellBorderStyle)
+	s.pdf.Rect(xPt, yPt, wPt, hPt, "D")
+	s.popStyle()
 }
 
-func (s *Stamp) restoreOutputStyle(style OutputStyle) {
-	style.setOutputStyle(s.pdf)
+// endTransform ends a transformation that was started with TransformBegin(). This
+// also restores the font from before the transformation in the PDF, while gofpdf
+// still assumes that the font set during the transformation is active. So the
+// font is set again to keep both in sync.
+func (s *Stamp) endTransform() {
+	s.pdf.TransformEnd()
+
+	fontsize, _ := s.pdf.GetFontSize()
+	s.pdf.SetFontSize(fontsize + 1.0)
+	s.pdf.SetFontSize(fontsize)
 }
 
 // DeriveFontsize checks whether the provided text fits into the given width, if the current
 // font and fontsize is used. If it does not fit, the size is reduced until it fits or until a
 // minimum font size is reached.
-func (s *Stamp) DeriveFontsize(cellWidthPt, cellHeightPt float64, font string, fontsize float64, text string) (result float64) {
+func (s *Stamp) DeriveFontsize(cellWidthPt, cellHeightPt float64, font, fontstyle string, fontsize float64, text string) (result float64) {
 	// TODO convert to percent and remove call from AddTextCell
 
 	currentFontsize := fontsize
@@ -130,82 +161,76 @@ func (s *Stamp) DeriveFontsize(cellWidthPt, cellHeightPt float64, font string, f
 	}
 
 	for ; currentFontsize >= minFontSize; currentFontsize -= 0.25 {
-		s.pdf.SetFont(font, "", currentFontsize)
-		if s.pdf.GetStringWidth(text) <= cellWidthPt {
+		tr := s.setFont(font, fontstyle, currentFontsize)
+		if s.pdf.GetStringWidth(tr(text)) <= cellWidthPt {
 			return currentFontsize
 		}
 	}
 	return minFontSize
 }
 
-// DeriveY2 takes two coordinates on the Y axis and the fontsize, and in case
-// the second coordinate is 0.0 will calculcate a proper y2 coordinate based
-// on y1 and the fontsize.
-func (s *Stamp) DeriveY2(canvasID string, y1Pct, y2Pct, fontsizePt float64) (y2 float64) {
+// DeriveY takes two coordinates on the Y axis, the fontsize and the alignment, and in
+// case the second coordinate is 0.0 will calculate a cell around the first coordinate
+// that is as high as the fontsize. Depending on the vertical alignment, the text is
+// placed above (bottom, baseline, default), below (top) or centered on (middle) y1.
+func (s *Stamp) DeriveY(canvasID string, y1Pct, y2Pct, fontsizePt float64, align string) (y1, y2 float64) {
 	if y2Pct != 0.0 {
-		return y2Pct
+		return y1Pct, y2Pct
 	}
 
 	_, fontsizePct := s.getCanvas(canvasID).relPtToPct(0.0, fontsizePt)
-	return y1Pct - fontsizePct
+	_, vertical, _ := parseAlign(align)
+	switch vertical {
+	case "T":
+		return y1Pct, y1Pct + fontsizePct
+	case "M":
+		return y1Pct - fontsizePct/2.0, y1Pct + fontsizePct/2.0
+	default:
+		return y1Pct, y1Pct - fontsizePct
+	}
 }
 
-// AddTextCell adds a text cell to the stamp.
-func (s *Stamp) AddTextCell(canvasID string, x1Pct, y1Pct, x2Pct, y2Pct float64, font string, fontsize float64, align string, text string, autoShrink bool) {
+// AddTextCell adds a text cell to the stamp. The text color and transparency are taken from the provided output style.
+// The text is rotated counter-clockwise by the provided angle in degrees around the center of
+// the cell. For rotations by 90 or 270 degrees, the text runs along the height of the cell.
+func (s *Stamp) AddTextCell(canvasID string, x1Pct, y1Pct, x2Pct, y2Pct float64, font, fontstyle string, fontsize float64, align string, rotation float64, text string, autoShrink bool, os OutputStyle) {
 	if !s.isActiveCanvas(canvasID) {
 		return
 	}
 
-	oldStyle := s.saveCurrentOutputStyle()
-	defer s.restoreOutputStyle(oldStyle)
+	style := os
+	style.DrawR, style.DrawG, style.DrawB = 0, 0, 0 // for the cell border
+	style.CellMargin = 0
+	s.pushStyle(style)
+	defer s.popStyle()
 
 	xPt, yPt, wPt, hPt := s.getCanvas(canvasID).transformToAbsXYWH(x1Pct, y1Pct, x2Pct, y2Pct)
 
-	effectiveFontsize := fontsize
-	if autoShrink {
-		effectiveFontsize = s.DeriveFontsize(wPt, hPt, font, fontsize, text)
-	}
-
-	s.pdf.SetFont(font, "", effectiveFontsize)
-	s.pdf.SetXY(xPt, yPt)
-	s.pdf.SetCellMargin(0)
-	if s.shouldDrawCellBorder() {
-		s.pdf.SetDrawColor(0, 0, 0)
-	}
-
-	s.pdf.CellFormat(wPt, hPt, s.tr(text), s.cellBorder, 0, align, false, 0, "")
-}
+	if rotation != 0.0 {
+		xCenterPt, yCenterPt := xPt+wPt/2.0, yPt+hPt/2.0
+		if math.Mod(math.Abs(rotation), 180.0) == 90.0 {
+			wPt, hPt = hPt, wPt
+		}
 
-// AddMultilineTextCell adds a text cell to the stamp.
-func (s *Stamp) AddMultilineTextCell(canvasID string, x1Pct, y1Pct, x2Pct, y2Pct float64, lineheightPct float64, font string, fontsize float64, align string, text string) {
-	if !s.isActiveCanvas(canvasID) {
-		return
+		// The unrotated cell may lie partially outside of the page, and gofpdf treats negative
+		// coordinates as relative to the right or bottom border. So the cell is positioned
+		// with its top left corner at the center and then moved via a transformation.
+		s.pdf.TransformBegin()
+		s.pdf.TransformRotate(rotation, xCenterPt, yCenterPt)
+		s.pdf.TransformTranslate(-wPt/2.0, -hPt/2.0)
+		defer s.endTransform()
+		xPt, yPt = xCenterPt, yCenterPt
 	}
 
-	canvas := s.getCanvas(canvasID)
-	xPt, yPt, wPt, _ := canvas.transformToAbsXYWH(x1Pct, y1Pct, x2Pct, y2Pct)
-	_, lhPt := canvas.pctToRelPt(0.0, lineheightPct)
-
 	effectiveFontsize := fontsize
-
-	oldStyle := s.saveCurrentOutputStyle()
-	defer s.restoreOutputStyle(oldStyle)
-
-	s.pdf.SetFont(font, "", effectiveFontsize)
-
-	s.pdf.SetXY(xPt, yPt)
-	fmt.Printf("Coords: %v x %v, %v, %v\n", xPt, yPt, y1Pct, y2Pct)
-	s.pdf.SetCellMargin(0)
-	if s.shouldDrawCellBorder() {
-		s.pdf.SetDrawColor(0, 0, 0)
+	if autoShrink {
+		effectiveFontsize = s.DeriveFontsize(wPt, hPt, font, fontstyle, fontsize, text)
 	}
 
-	s.pdf.MultiCell(wPt, lhPt, text, s.cellBorder, align, false)
-
-	s.pdf.SetXY(400.0, 400.0)
-	s.pdf.MultiCell(20.0, 14.0, "Some very, very long text is this", "", "", true)
-	// https://godoc.org/github.com/jung-kurt/gofpdf#Fpdf.MultiCell
-	// func (f *Fpdf) MultiCell(w, h float64, txtStr, borderStr, alignStr string, fill bool)
+	tr := s.setFont(font, fontstyle, effectiveFontsize)
+	s.pdf.SetXY(xPt, yPt)
+	s.pdf.CellFormat(wPt, hPt, tr(text), s.cellBorder, 0, align, false, 0, "")
+	s.addRecord("text", canvasID, text, effectiveFontsize, effectiveFontsize < fontsize && effectiveFontsize <= minFontSize, false)
 }
 
 // DrawRectangle draws a rectangle on the stamp.
@@ -216,14 +241,11 @@ func (s *Stamp) DrawRectangle(canvasID string, x1Pct, y1Pct, x2Pct, y2Pct float6
 
 	xPt, yPt, wPt, hPt := s.getCanvas(canvasID).transformToAbsXYWH(x1Pct, y1Pct, x2Pct, y2Pct)
 
-	oldStyle := s.saveCurrentOutputStyle()
-	defer s.restoreOutputStyle(oldStyle)
-
-	s.pdf.SetDrawColor(os.DrawR, os.DrawG, os.DrawB)
-	s.pdf.SetFillColor(os.FillR, os.FillG, os.FillB)
-	s.pdf.SetAlpha(1.0-os.Transparency, "Normal")
+	s.pushStyle(os)
+	defer s.popStyle()
 
 	s.pdf.Rect(xPt, yPt, wPt, hPt, os.Style)
+	s.addRecord("rectangle", canvasID, "", 0.0, false, false)
 }
 
 // DrawLine draws a line on the stamp.
@@ -236,27 +258,18 @@ func (s *Stamp) DrawLine(canvasID string, x1Pct, y1Pct, x2Pct, y2Pct float64, os
 	x1Pt, y1Pt := canvas.pctToAbsPt(x1Pct, y1Pct)
 	x2Pt, y2Pt := canvas.pctToAbsPt(x2Pct, y2Pct)
 
-	oldStyle := s.saveCurrentOutputStyle()
-	defer s.restoreOutputStyle(oldStyle)
-
-	s.pdf.SetDrawColor(os.DrawR, os.DrawG, os.DrawB)
-	s.pdf.SetLineWidth(os.Linewidth)
+	s.pushStyle(os)
+	defer s.popStyle()
 
 	s.pdf.Line(x1Pt, y1Pt, x2Pt, y2Pt)
+	s.addRecord("line", canvasID, "", 0.0, false, false)
 }
 
 func (s *Stamp) drawStrikeoutInternal(x1Pt, y1Pt, x2Pt, y2Pt float64, os OutputStyle) {
-	oldStyle := s.saveCurrentOutputStyle()
-	defer s.restoreOutputStyle(oldStyle)
+	s.drawCellBorder(x1Pt, y1Pt, x2Pt-x1Pt, y2Pt-y1Pt)
 
-	if s.shouldDrawCellBorder() {
-		s.pdf.SetDrawColor(0, 0, 0)
-		s.pdf.SetLineWidth(0.5)
-		s.pdf.Rect(x1Pt, y1Pt, x2Pt-x1Pt, y2Pt-y1Pt, "D")
-	}
-
-	s.pdf.SetDrawColor(os.DrawR, os.DrawG, os.DrawB)
-	s.pdf.SetLineWidth(os.Linewidth)
+	s.pushStyle(os)
+	defer s.popStyle()
 
 	s.pdf.Line(x1Pt, y1Pt, x2Pt, y2Pt)
 	s.pdf.Line(x1Pt, y2Pt, x2Pt, y1Pt)
@@ -273,6 +286,7 @@ func (s *Stamp) DrawStrikeoutArea(canvasID string, x1Pct, y1Pct, x2Pct, y2Pct fl
 	x2Pt, y2Pt := canvas.pctToAbsPt(x2Pct, y2Pct)
 
 	s.drawStrikeoutInternal(x1Pt, y1Pt, x2Pt, y2Pt, os)
+	s.addRecord("strikeout", canvasID, "", 0.0, false, false)
 }
 
 // DrawStrikeoutCentered draws a strikeout cross on the stamp around an area
@@ -290,17 +304,16 @@ func (s *Stamp) DrawStrikeoutCentered(canvasID string, xPct, yPct, sizePt float6
 	y2Pt := yCenterPt + halfSize
 
 	s.drawStrikeoutInternal(x1Pt, y1Pt, x2Pt, y2Pt, os)
+	s.addRecord("strikeout", canvasID, "", 0.0, false, false)
 }
 
 // DrawCanvases draws all canvases to the stamp
 func (s *Stamp) DrawCanvases() {
-	oldStyle := s.saveCurrentOutputStyle()
-	defer s.restoreOutputStyle(oldStyle)
-
 	fontsize := 8.0
 	r, g, b := 51, 204, 51
-	s.pdf.SetDrawColor(r, g, b)
-	s.pdf.SetFont("Helvetica", "", fontsize)
+	s.pushStyle(OutputStyle{DrawR: r, DrawG: g, DrawB: b, TextR: r, TextG: g, TextB: b, Font: getLabelFont(""), Fontsize: fontsize})
+	defer s.popStyle()
+	tr := s.setFont(getLabelFont(""), "", fontsize)
 
 	for canvasID, canvas := range s.canvasStore {
 		if !s.isActiveCanvas(canvasID) {
@@ -314,11 +327,16 @@ func (s *Stamp) DrawCanvases() {
 
 		// name/ID
 		s.pdf.SetXY(xPt, yPt+hPt-fontsize)
-		s.pdf.SetTextColor(r, g, b)
-		s.pdf.CellFormat(wPt, fontsize, s.tr(canvasID), "0", 0, "RM", false, 0, "")
+		s.pdf.CellFormat(wPt, fontsize, tr(canvasID), "0", 0, "RM", false, 0, "")
 	}
 }
 
+// Err returns the first error that occurred while adding content to the stamp, e.g.
+// when a font file could not be embedded.
+func (s *Stamp) Err() (err error) {
+	return s.pdf.Error()
+}
+
 // WriteToFile writes the content of the Stamp object into a PDF file.
 // The Stamp object should not be used anymore after that.
 func (s *Stamp) WriteToFile(filename string) (err error) {
@@ -332,7 +350,6 @@ func (s *Stamp) WriteToFile(filename string) (err error) {
 // DrawCanvasGrid overlays the stamp with a set of lines
 func (s *Stamp) DrawCanvasGrid(canvasID string) (err error) {
 	const (
-		labelFont      = "Arial"
 		labelFontStyle = "B"
 		labelFontSize  = float64(6)
 		borderAreaPt   = float64(16) // do not add lines and text if that near to the page border
@@ -342,9 +359,6 @@ func (s *Stamp) DrawCanvasGrid(canvasID string) (err error) {
 		extraSpace = float64(1.0) // points
 	)
 
-	oldStyle := s.saveCurrentOutputStyle()
-	defer s.restoreOutputStyle(oldStyle)
-
 	// find canvas for which we should draw the grid
 	var canvas canvas
 	if canvasID != "" {
@@ -392,23 +406,19 @@ func (s *Stamp) DrawCanvasGrid(canvasID string) (err error) {
 		}
 	}
 
-	// store away old relevant settings and reset at the end (you never know...)
-	formerDR, formerDB, formerDG := s.pdf.GetDrawColor()
-	defer s.pdf.SetDrawColor(formerDR, formerDG, formerDB)
-	formerFR, formerFB, formerFG := s.pdf.GetFillColor()
-	defer s.pdf.SetFillColor(formerFR, formerFG, formerFB)
-	formerLineWidth := s.pdf.GetLineWidth()
-	defer s.pdf.SetLineWidth(formerLineWidth)
-
-	s.pdf.SetFont(labelFont, labelFontStyle, labelFontSize) // Used for the labels at the borders
+	// settings for minor gap drawing, font is used for the labels at the borders
+	s.pushStyle(OutputStyle{
+		DrawR: 196, DrawG: 196, DrawB: 196, // something lightgrayish
+		Linewidth: minorLineWidth,
+		Font:      getLabelFont(labelFontStyle),
+		Fontstyle: labelFontStyle,
+		Fontsize:  labelFontSize,
+	})
+	defer s.popStyle()
 
 	maxLabelWidth := s.pdf.GetStringWidth("x:99") + extraSpace // 100% won't be reached
 	maxLabelHeight := labelFontSize + extraSpace
 
-	// settings for minor gap drawing
-	s.pdf.SetDrawColor(196, 196, 196) // something lightgrayish
-	s.pdf.SetLineWidth(minorLineWidth)
-
 	// minor vertical lines
 	for curPercent := 0.0; curPercent <= 100.0; curPercent += minorGapVertical {
 		// dont't draw anything directly on the border
@@ -432,10 +442,12 @@ func (s *Stamp) DrawCanvasGrid(canvasID string) (err error) {
 	}
 
 	// settings for major gap drawing
-	s.pdf.SetDrawColor(64, 64, 255)   // something blueish
-	s.pdf.SetFillColor(255, 255, 255) // white
-	s.pdf.SetCellMargin(0)
-	s.pdf.SetLineWidth(majorLineWidth)
+	s.pushStyle(OutputStyle{
+		DrawR: 64, DrawG: 64, DrawB: 255, // something blueish
+		FillR: 255, FillG: 255, FillB: 255, // white
+		Linewidth: majorLineWidth,
+	})
+	defer s.popStyle()
 
 	// major vertical lines
 	for curPercent := 0.0; curPercent <= 100.0; curPercent += majorGapVertical {
@@ -45,7 +45,7 @@ func TestStamp_DetermineFontSize(t *testing.T) {
 		{100.0, 10.0, 14.0, "foo", 10.0},
 		{100.0, 2.0, 14.0, "foo", minFontSize},
 	} {
-		result = s.DeriveFontsize(tt.width, tt.height, "Arial", tt.fontsize, tt.text)
+		result = s.DeriveFontsize(tt.width, tt.height, "Arial", "", tt.fontsize, tt.text)
 		test.ExpectEqual(t, result, tt.expectedFontsize)
 	}
 }
@@ -103,3 +103,47 @@ func TestStamp_DrawCanvasGrid(t *testing.T) {
 		test.ExpectError(t, err, "Cannot find", canvasID)
 	})
 }
+
+func TestStamp_Records(t *testing.T) {
+	t.Run("recording disabled", func(t *testing.T) {
+		s := NewStamp(400.0, 400.0, 0.0, 0.0)
+		s.AddCanvas("page", 0.0, 0.0, 100.0, 100.0)
+		s.AddTextCell("page", 0.0, 0.0, 50.0, 5.0, "Arial", "", 14.0, "LB", 0.0, "foo", true, OutputStyle{})
+		test.ExpectEqual(t, len(s.GetRecords()), 0)
+	})
+
+	t.Run("recording enabled", func(t *testing.T) {
+		s := NewStamp(400.0, 400.0, 0.0, 0.0)
+		s.SetRecording(true)
+		s.AddCanvas("page", 0.0, 0.0, 100.0, 100.0)
+
+		s.EnterContent("0")
+		s.AddTextCell("page", 0.0, 0.0, 50.0, 5.0, "Arial", "", 14.0, "LB", 0.0, "foo", true, OutputStyle{})
+		s.LeaveContent()
+
+		s.EnterContent("1")
+		s.EnterContent("2")
+		s.AddTextCell("page", 0.0, 0.0, 1.0, 5.0, "Arial", "", 14.0, "LB", 0.0, "fooooooooooooooooooooooo", true, OutputStyle{})
+		s.LeaveContent()
+		s.DrawLine("page", 0.0, 0.0, 100.0, 100.0, OutputStyle{})
+		s.LeaveContent()
+
+		records := s.GetRecords()
+		test.ExpectEqual(t, len(records), 3)
+
+		test.ExpectEqual(t, records[0].Content, "0")
+		test.ExpectEqual(t, records[0].Type, "text")
+		test.ExpectEqual(t, records[0].Canvas, "page")
+		test.ExpectEqual(t, records[0].Value, "foo")
+		test.ExpectEqual(t, records[0].Fontsize, 14.0)
+		test.ExpectFalse(t, records[0].Shrunk)
+
+		test.ExpectEqual(t, records[1].Content, "1/2")
+		test.ExpectEqual(t, records[1].Fontsize, minFontSize)
+		test.ExpectTrue(t, records[1].Shrunk)
+
+		test.ExpectEqual(t, records[2].Content, "1")
+		test.ExpectEqual(t, records[2].Type, "line")
+		test.ExpectEqual(t, records[2].Value, "")
+	})
+}
@@ -9,12 +9,14 @@ import (
 	"github.com/Blesmol/pfscf/pfscf/args"
 	"github.com/Blesmol/pfscf/pfscf/canvas"
 	"github.com/Blesmol/pfscf/pfscf/cfg"
+	"github.com/Blesmol/pfscf/pfscf/color"
 	"github.com/Blesmol/pfscf/pfscf/content"
 	"github.com/Blesmol/pfscf/pfscf/csv"
 	"github.com/Blesmol/pfscf/pfscf/param"
 	"github.com/Blesmol/pfscf/pfscf/preset"
 	"github.com/Blesmol/pfscf/pfscf/stamp"
 	"github.com/Blesmol/pfscf/pfscf/utils"
+	"github.com/Blesmol/pfscf/pfscf/yaml"
 )
 
 const (
@@ -36,6 +38,7 @@ type Chronicle struct {
 	Aspectratio   string
 	Flags         []string
 	Parameters    param.Store
+	Colors        color.Store
 	Presets       preset.Store
 	Canvas        canvas.Store
 	Content       content.ListStore
@@ -60,7 +63,7 @@ func templateErr(ct *Chronicle, errIn error) (errOut error) {
 }
 
 func templateErrf(ct *Chronicle, msg string, args ...interface{}) (errOut error) {
-	return fmt.Errorf("Template '%v': "+msg, ct.ID, args)
+	return fmt.Errorf("Template '%v': "+msg, append([]interface{}{ct.ID}, args...)...)
 }
 
 // ensureStoresAreInitialized is a workaround for the behavior of the stupid f... yaml library.
@@ -72,6 +75,9 @@ func (ct *Chronicle) ensureStoresAreInitialized() {
 	if ct.Parameters == nil {
 		ct.Parameters = param.NewStore()
 	}
+	if ct.Colors == nil {
+		ct.Colors = color.NewStore()
+	}
 	if ct.Presets == nil {
 		ct.Presets = preset.NewStore()
 	}
@@ -98,14 +104,16 @@ func (ct *Chronicle) GetExampleArguments() (result []string) {
 // inheritFrom inherits entries from multiple sections from another
 // ChronicleTemplate object. An error is returned in case a content
 // entry from sections 'parameters' or 'content' exists in both objects.
-// In case a preset entry exists in both objects, then the one from the original
+// In case a preset or color entry exists in both objects, then the one from the original
 // object takes precedence.
 func (ct *Chronicle) inheritFrom(otherCT *Chronicle) (err error) {
 	err = ct.Parameters.InheritFrom(&otherCT.Parameters)
 	if err != nil {
-		return templateErr(ct, err)
+		return yaml.NewPathError(err, "parameters")
 	}
 
+	ct.Colors.InheritFrom(otherCT.Colors)
+
 	ct.Presets.InheritFrom(otherCT.Presets)
 
 	ct.Canvas.InheritFrom(otherCT.Canvas)
@@ -119,22 +127,25 @@ func (ct *Chronicle) inheritFrom(otherCT *Chronicle) (err error) {
 	return nil
 }
 
-// resolve resolves this template. This means that preset dependencies are resolved
-// and after that the preset dependencies on content side. Currently nothing needs
-// to be done for parameters.
-func (ct *Chronicle) resolve() (err error) {
-	if err = ct.Presets.Resolve(); err != nil {
-		return templateErr(ct, err)
+// resolveAll resolves this template. This means that preset dependencies are resolved
+// and after that the preset dependencies and named colors on content side. Currently
+// nothing needs to be done for parameters.
+// All errors found are returned. Each error is of type *yaml.PathError and points to
+// the affected entry within the template file.
+func (ct *Chronicle) resolveAll() (errs []error) {
+	for _, err := range ct.Presets.ResolveAll() {
+		errs = append(errs, yaml.NewPathError(err, "presets"))
 	}
 
-	if err = ct.Canvas.Resolve(); err != nil {
-		return templateErr(ct, err)
+	for _, err := range ct.Canvas.ResolveAll() {
+		errs = append(errs, yaml.NewPathError(err, "canvas"))
 	}
 
-	if err = ct.Content.Resolve(ct.Presets); err != nil {
-		return templateErr(ct, err)
+	for _, err := range ct.Content.ResolveAll(ct.Presets, ct.Colors) {
+		errs = append(errs, yaml.NewPathError(err, "content"))
 	}
-	return nil
+
+	return errs
 }
 
 // GenerateCsvFile creates a CSV file out of the current chronicle template than can be used
@@ -192,8 +203,8 @@ func (ct *Chronicle) GenerateCsvFile(filename string, separator rune, argStore *
 					}
 				}
 
-				// add example text
-				row[len(row)-1] = fmt.Sprintf("# %v", paramEntry.Example())
+				// add example text, and mark required parameters
+				row[len(row)-1] = fmt.Sprintf("# %v%v", paramEntry.Example(), param.UsageNote(paramEntry))
 
 				records = append(records, row)
 			}
@@ -264,35 +275,49 @@ func (ct *Chronicle) GenerateOutput(stamp *stamp.Stamp, argStore *args.Store) (e
 }
 
 // IsValid checks whether a given chronicle is valid. This should only be called
-// after resolve() was called on this template.
+// after the template was resolved.
 func (ct *Chronicle) IsValid() (err error) {
+	if errs := ct.validateAll(); len(errs) > 0 {
+		return templateErr(ct, errs[0])
+	}
+	return nil
+}
+
+// validateAll checks whether a given chronicle is valid and returns all errors found.
+// Each error is of type *yaml.PathError and points to the affected entry within the
+// template file. This should only be called after the template was resolved.
+func (ct *Chronicle) validateAll() (errs []error) {
 	if utils.IsSet(ct.Aspectratio) {
-		if _, _, err = parseAspectRatio(ct.Aspectratio); err != nil {
-			return templateErr(ct, err)
+		if _, _, err := parseAspectRatio(ct.Aspectratio); err != nil {
+			errs = append(errs, yaml.NewPathError(err, "aspectratio"))
 		}
 	}
 
 	if !utils.IsSet(ct.Description) {
-		return templateErrf(ct, "Missing description")
+		errs = append(errs, yaml.NewPathError(fmt.Errorf("Missing description"), "description"))
 	}
 
-	if err = ct.hasValidFlags(); err != nil {
-		return templateErr(ct, err)
+	if err := ct.hasValidFlags(); err != nil {
+		errs = append(errs, yaml.NewPathError(err, "flags"))
 	}
 
-	if err = ct.Parameters.IsValid(); err != nil {
-		return templateErr(ct, err)
+	for _, err := range ct.Parameters.ValidateAll() {
+		errs = append(errs, yaml.NewPathError(err, "parameters"))
 	}
 
-	if err = ct.Canvas.IsValid(); err != nil {
-		return templateErr(ct, err)
+	for _, err := range ct.Colors.ValidateAll() {
+		errs = append(errs, yaml.NewPathError(err, "colors"))
 	}
 
-	if err = ct.Content.IsValid(&ct.Parameters, &ct.Canvas); err != nil {
-		return templateErr(ct, err)
+	if err := ct.Canvas.IsValid(); err != nil {
+		errs = append(errs, yaml.NewPathError(err, "canvas"))
 	}
 
-	return nil
+	for _, err := range ct.Content.ValidateAll(&ct.Parameters, &ct.Canvas) {
+		errs = append(errs, yaml.NewPathError(err, "content"))
+	}
+
+	return errs
 }
 
 // Describe returns a short textual description of a single chronicle template.
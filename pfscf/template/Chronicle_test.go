@@ -78,6 +78,105 @@ canvas:
 	})
 }
 
+func TestChronicleTemplate_colors(t *testing.T) {
+	parentYaml := `
+id: parent
+description: some description
+
+colors:
+  highlight: "#ffcc00"
+  paizo_red: rgb(161, 43, 43)
+
+canvas:
+  page:
+    x: 0.0
+    y: 0.0
+    x2: 100.0
+    y2: 100.0
+
+presets:
+  highlighted:
+    color: highlight
+`
+
+	getChildTemplate := func(t *testing.T, childYaml string) (ct *Chronicle) {
+		t.Helper()
+
+		parentTemplate := NewChronicleTemplate("parent.yml")
+		test.ExpectNoError(t, yaml.Unmarshal([]byte(parentYaml), &parentTemplate))
+		parentTemplate.ensureStoresAreInitialized()
+
+		childTemplate := NewChronicleTemplate("child.yml")
+		test.ExpectNoError(t, yaml.Unmarshal([]byte(childYaml), &childTemplate))
+		childTemplate.ensureStoresAreInitialized()
+
+		test.ExpectNoError(t, childTemplate.inheritFrom(&parentTemplate))
+		test.ExpectEqual(t, len(childTemplate.resolveAll()), 0)
+		return &childTemplate
+	}
+
+	t.Run("errors", func(t *testing.T) {
+		t.Run("invalid color definition", func(t *testing.T) {
+			ct := getChildTemplate(t, `
+id: child
+description: some description
+colors:
+  highlight: "#ffcc0"
+`)
+			errs := ct.validateAll()
+			test.ExpectEqual(t, len(errs), 1)
+			test.ExpectError(t, errs[0], "Unknown color: '#ffcc0'")
+		})
+
+		t.Run("unknown color name", func(t *testing.T) {
+			ct := getChildTemplate(t, `
+id: child
+description: some description
+content:
+  - type: line
+    x: 10.0
+    y: 10.0
+    x2: 20.0
+    y2: 10.0
+    linewidth: 1.0
+    color: paizo_blue
+    canvas: page
+`)
+			errs := ct.validateAll()
+			test.ExpectEqual(t, len(errs), 1)
+			test.ExpectError(t, errs[0], "Unknown color: 'paizo_blue'")
+		})
+	})
+
+	t.Run("valid", func(t *testing.T) {
+		ct := getChildTemplate(t, `
+id: child
+description: some description
+colors:
+  highlight: yellow
+content:
+  - type: line
+    x: 10.0
+    y: 10.0
+    x2: 20.0
+    y2: 10.0
+    linewidth: 1.0
+    color: paizo_red
+    canvas: page
+  - type: rectangle
+    x: 10.0
+    y: 10.0
+    x2: 20.0
+    y2: 20.0
+    presets: [highlighted]
+    canvas: page
+`)
+		test.ExpectEqual(t, len(ct.validateAll()), 0)
+		test.ExpectEqual(t, ct.Colors["highlight"], "yellow")
+		test.ExpectEqual(t, ct.Colors["paizo_red"], "rgb(161, 43, 43)")
+	})
+}
+
 func TestParseAspectRatio(t *testing.T) {
 	t.Run("errors", func(t *testing.T) {
 		testData := []struct{ input, errString string }{
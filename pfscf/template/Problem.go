@@ -0,0 +1,74 @@
+package template
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/Blesmol/pfscf/pfscf/utils"
+	"github.com/Blesmol/pfscf/pfscf/yaml"
+)
+
+// Problem describes a single issue that was found while reading, resolving
+// or validating a template file.
+type Problem struct {
+	TemplateID string // empty if the issue cannot be attributed to a template, e.g. for unparseable files
+	Filename   string
+	Line       int // 0 if unknown
+	Err        error
+}
+
+// newFileProblem creates a problem for a file that could not be read or parsed at all.
+func newFileProblem(filename string, err error) (p Problem) {
+	p = Problem{Filename: filename, Err: err}
+	if pe, ok := err.(*yaml.ParseError); ok {
+		p.Line = pe.Line
+		p.Err = pe.Err
+	}
+	return p
+}
+
+// newTemplateProblem creates a problem for the provided template. If the error is a
+// *yaml.PathError, then the line is determined from the path. Otherwise the line
+// containing the template ID is used.
+func newTemplateProblem(ct *Chronicle, err error) (p Problem) {
+	path := []string{"id"}
+	if pe, ok := err.(*yaml.PathError); ok {
+		path = pe.Path
+		err = pe.Err
+	}
+
+	line, lineErr := yaml.GetLineForPath(ct.filename, path...)
+	if lineErr != nil {
+		line = 0 // should not happen, as the file was already read successfully before
+	}
+
+	return Problem{TemplateID: ct.ID, Filename: ct.filename, Line: line, Err: err}
+}
+
+// Error returns the problem in the format "<file>:<line>: Template '<id>': <message>".
+func (p Problem) Error() string {
+	var sb strings.Builder
+
+	fmt.Fprint(&sb, p.Filename)
+	if p.Line > 0 {
+		fmt.Fprintf(&sb, ":%d", p.Line)
+	}
+	fmt.Fprint(&sb, ": ")
+	if utils.IsSet(p.TemplateID) {
+		fmt.Fprintf(&sb, "Template '%v': ", p.TemplateID)
+	}
+	fmt.Fprint(&sb, p.Err)
+
+	return sb.String()
+}
+
+// sortProblems sorts the provided list of problems by filename and line.
+func sortProblems(problems []Problem) {
+	sort.SliceStable(problems, func(i, j int) bool {
+		if problems[i].Filename != problems[j].Filename {
+			return problems[i].Filename < problems[j].Filename
+		}
+		return problems[i].Line < problems[j].Line
+	})
+}
@@ -2,6 +2,7 @@ package template
 
 import (
 	"fmt"
+	"path/filepath"
 	"sort"
 	"strings"
 
@@ -11,117 +12,396 @@ import (
 )
 
 // Store stores multiple ChronicleTemplates and provides means
-// to retrieve them by name.
-type Store map[string]*Chronicle // Store as ptrs so that it is easier to modify them do things like aliasing
+// to retrieve them by name. Templates that showed problems while loading
+// are not contained, but the problems are kept for later reference.
+type Store struct {
+	templates map[string]*Chronicle // Store as ptrs so that it is easier to modify them do things like aliasing
+	problems  []Problem
+	overrides []Override
+}
+
+// Override describes a template that was defined in multiple template directories.
+// The template from the directory with the highest priority wins.
+type Override struct {
+	TemplateID string
+	Filename   string   // the file containing the template that is used
+	Overridden []string // the files containing templates that are overridden, ordered by priority
+}
 
 // newStore creates a new Store object
 func newStore() (store *Store) {
-	s := make(Store, 0)
-	return &s
+	return &Store{
+		templates: make(map[string]*Chronicle, 0),
+		problems:  make([]Problem, 0),
+		overrides: make([]Override, 0),
+	}
 }
 
 // GetStore returns a template store that is already filled with all templates
-// contained in the main template directory. If some error showed up during reading and
-// parsing files, resolving dependencies etc, then nil is returned together with an error.
+// contained in the configured template directories, see cfg.GetTemplateDirs().
+// Templates for which problems showed up during reading and parsing files, resolving
+// dependencies etc are skipped, see Problems(). An error is only returned if a
+// template directory cannot be read.
 func GetStore() (ts *Store, err error) {
-	return getStoreForDir(cfg.GetTemplatesDir())
+	dirs, err := getTemplateDirPaths()
+	if err != nil {
+		return nil, err
+	}
+	return getStoreForDirs(dirs...)
+}
+
+// Validate reads all templates from the configured template directories and returns a list of
+// all problems found, sorted by filename and line. If template IDs are provided, then
+// only problems for these templates are returned. An error is returned in case the
+// templates could not be read at all or in case a provided template ID is unknown.
+func Validate(ids ...string) (problems []Problem, err error) {
+	dirs, err := getTemplateDirPaths()
+	if err != nil {
+		return nil, err
+	}
+	return validateDirs(dirs, ids...)
 }
 
-// Get returns the ChronicleTemplate matching the provided id.
+func getTemplateDirPaths() (paths []string, err error) {
+	dirs, err := cfg.GetTemplateDirs()
+	if err != nil {
+		return nil, err
+	}
+
+	paths = make([]string, 0, len(dirs))
+	for _, dir := range dirs {
+		paths = append(paths, dir.Path)
+	}
+	return paths, nil
+}
+
+// Get returns the ChronicleTemplate matching the provided id. Templates
+// with problems are not returned.
 func (s *Store) Get(id string) (ct *Chronicle, exists bool) {
-	ct, exists = (*s)[id]
+	ct, exists = s.templates[id]
 	return
 }
 
-// getStoreForDir takes a directory and returns a template store
-// for all entries in that directory, including its subdirectories
-func getStoreForDir(dir string) (store *Store, err error) {
-	filenames, err := yaml.GetYamlFilenamesFromDir(dir)
+// Select returns the ChronicleTemplate matching the provided id. In contrast to Get(),
+// an error is returned if no such template exists, and if the template was skipped due to
+// problems, then the error contains these problems.
+func (s *Store) Select(id string) (ct *Chronicle, err error) {
+	if ct, exists := s.Get(id); exists {
+		return ct, nil
+	}
+
+	if problems := s.GetProblemsFor(id); len(problems) > 0 {
+		var sb strings.Builder
+		fmt.Fprintf(&sb, "Template '%v' cannot be used due to the following problem(s):", id)
+		for _, problem := range problems {
+			fmt.Fprintf(&sb, "\n- %v", problem)
+		}
+		return nil, fmt.Errorf("%v", sb.String())
+	}
+
+	return nil, s.notFoundError(id)
+}
+
+// notFoundError returns an error for a template ID that is completely unknown to the store.
+func (s *Store) notFoundError(id string) (err error) {
+	err = fmt.Errorf("Cannot find template '%v'", id)
+	if numFileProblems := countFileProblems(s.problems); numFileProblems > 0 {
+		err = fmt.Errorf("%v; %v template file(s) could not be read, perhaps it is contained in one of them", err, numFileProblems)
+	}
+	return err
+}
+
+// Problems returns all problems that showed up while loading the templates,
+// sorted by filename and line.
+func (s *Store) Problems() (problems []Problem) {
+	return append(make([]Problem, 0, len(s.problems)), s.problems...)
+}
+
+// GetProblemsFor returns all problems that showed up for the template with the provided ID.
+func (s *Store) GetProblemsFor(id string) (problems []Problem) {
+	problems = make([]Problem, 0)
+	for _, problem := range s.problems {
+		if problem.TemplateID == id {
+			problems = append(problems, problem)
+		}
+	}
+	return problems
+}
+
+// Overrides returns all templates that were defined in multiple template directories,
+// sorted by template ID.
+func (s *Store) Overrides() (overrides []Override) {
+	return append(make([]Override, 0, len(s.overrides)), s.overrides...)
+}
+
+// getStoreForDirs takes a list of directories and returns a template store
+// for all entries in these directories, including their subdirectories
+func getStoreForDirs(dirs ...string) (store *Store, err error) {
+	store, problems, err := loadStoreForDirs(dirs...)
+	if err != nil {
+		return nil, err
+	}
+	store.problems = problems
+
+	return store, nil
+}
+
+// validateDirs returns all problems found for the templates in the provided directories,
+// optionally restricted to the provided template IDs.
+func validateDirs(dirs []string, ids ...string) (problems []Problem, err error) {
+	store, err := getStoreForDirs(dirs...)
 	if err != nil {
 		return nil, err
 	}
 
+	if len(ids) == 0 {
+		return store.Problems(), nil
+	}
+
+	problems = make([]Problem, 0)
+	for _, id := range ids {
+		idProblems := store.GetProblemsFor(id)
+		if _, exists := store.Get(id); !exists && len(idProblems) == 0 {
+			return nil, store.notFoundError(id)
+		}
+		problems = append(problems, idProblems...)
+	}
+	sortProblems(problems)
+
+	return problems, nil
+}
+
+func countFileProblems(problems []Problem) (count int) {
+	for _, problem := range problems {
+		if !utils.IsSet(problem.TemplateID) {
+			count++
+		}
+	}
+	return count
+}
+
+// loadStoreForDirs reads all template files from the provided directories and their subdirectories
+// and resolves and validates the contained templates. Directories are provided in ascending order
+// of priority, so a template from a later directory overrides a template with the same ID from an
+// earlier directory. All problems found are returned, sorted by filename and line. Templates with
+// problems are not included in the returned store, and neither are templates that inherit from them.
+// An error is only returned if a directory itself cannot be read.
+func loadStoreForDirs(dirs ...string) (store *Store, problems []Problem, err error) {
 	store = newStore()
+	problems = make([]Problem, 0)
+	overrides := make(map[string]*Override)
+
+	for _, dir := range dirs {
+		dirTemplates, dirProblems, err := readTemplatesFromDir(dir)
+		if err != nil {
+			return nil, nil, err
+		}
+		problems = append(problems, dirProblems...)
+
+		for id, ct := range dirTemplates {
+			if other, exists := store.Get(id); exists {
+				if _, isKnown := overrides[id]; !isKnown {
+					overrides[id] = &Override{TemplateID: id, Overridden: make([]string, 0)}
+				}
+				overrides[id].Overridden = append(overrides[id].Overridden, other.filename)
+			}
+			store.templates[id] = ct
+		}
+
+		// templates with duplicate IDs within a directory also hide the
+		// templates with the same ID from directories with a lower priority
+		for _, problem := range dirProblems {
+			if _, isDirTemplate := dirTemplates[problem.TemplateID]; utils.IsSet(problem.TemplateID) && !isDirTemplate {
+				delete(store.templates, problem.TemplateID)
+				delete(overrides, problem.TemplateID)
+			}
+		}
+	}
+
+	for id, override := range overrides {
+		if ct, exists := store.Get(id); exists {
+			override.Filename = ct.filename
+			store.overrides = append(store.overrides, *override)
+		}
+	}
+	sort.Slice(store.overrides, func(i, j int) bool {
+		return store.overrides[i].TemplateID < store.overrides[j].TemplateID
+	})
+
+	problems = append(problems, store.resolve()...)
+	sortProblems(problems)
+
+	return store, problems, nil
+}
+
+// readTemplatesFromDir reads all template files from the provided directory and its subdirectories.
+// Templates that could not be read are not returned, but the problems for them are. Templates
+// having the same ID are reported as problem, as we cannot know which one is the correct one.
+func readTemplatesFromDir(dir string) (templates map[string]*Chronicle, problems []Problem, err error) {
+	filenames, err := yaml.GetYamlFilenamesFromDir(dir)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	templates = make(map[string]*Chronicle)
+	problems = make([]Problem, 0)
+	duplicates := make(map[string][]*Chronicle)
 
-	// read all templates from files and put into store
 	for _, filename := range filenames {
 		ct := NewChronicleTemplate(filename)
-		err = yaml.ReadYamlFile(filename, &ct)
-		if err != nil {
-			return nil, err
+		if err = yaml.ReadYamlFile(filename, &ct); err != nil {
+			problems = append(problems, newFileProblem(filename, err))
+			continue
 		}
 		ct.ensureStoresAreInitialized() // workaround for bug / shitty behavior in go-yaml
+		ct.Content.SetBaseDir(filepath.Dir(filename))
 
-		// check for duplicate IDs
-		if other, exists := store.Get(ct.ID); exists {
-			return nil, fmt.Errorf("Found multiple templates with ID '%v':\n- %v\n- %v", ct.ID, ct.filename, other.filename)
+		if !utils.IsSet(ct.ID) {
+			problems = append(problems, newFileProblem(filename, fmt.Errorf("Missing template ID")))
+			continue
 		}
 
-		(*store)[ct.ID] = &ct
-	}
+		// collect duplicate IDs, these are sorted out afterwards
+		if other, exists := templates[ct.ID]; exists {
+			if _, isKnown := duplicates[ct.ID]; !isKnown {
+				duplicates[ct.ID] = []*Chronicle{other}
+			}
+			duplicates[ct.ID] = append(duplicates[ct.ID], &ct)
+			continue
+		}
 
-	if err = store.resolve(); err != nil {
-		return nil, err
+		templates[ct.ID] = &ct
 	}
 
-	if err = store.isValid(); err != nil {
-		return nil, err
+	for id, dupList := range duplicates {
+		for _, ct := range dupList {
+			err = fmt.Errorf("Found multiple templates with ID '%v' in files %v", id, getFilenames(dupList))
+			problems = append(problems, newTemplateProblem(ct, err))
+		}
+		delete(templates, id)
 	}
 
-	return store, nil
+	return templates, problems, nil
 }
 
-func (s *Store) resolve() (err error) {
-	// resolve references between templates
-	for _, ct := range *s {
+func getFilenames(cts []*Chronicle) (filenames []string) {
+	filenames = make([]string, 0)
+	for _, ct := range cts {
+		filenames = append(filenames, ct.filename)
+	}
+	return filenames
+}
+
+// resolve resolves inheritance and display dependencies between templates, then resolves
+// and validates each template. Templates with problems are removed from the store, and so are
+// all templates that inherit from them. All problems found are returned.
+func (s *Store) resolve() (problems []Problem) {
+	problems = make([]Problem, 0)
+	broken := make(map[string]bool)
+	markBroken := func(ct *Chronicle, err error) {
+		problems = append(problems, newTemplateProblem(ct, err))
+		broken[ct.ID] = true
+	}
+
+	// check references between templates
+	for _, ct := range s.getSortedList() {
 		// DisplayParent is equal to Parent if not set explicitly
 		if utils.IsSet(ct.Parent) && !utils.IsSet(ct.DisplayParent) {
 			ct.DisplayParent = ct.Parent
 		}
 
 		if utils.IsSet(ct.Parent) {
-			// check if parent actually exists, and if so, add chronicle reference
-			parentCt, exists := s.Get(ct.Parent)
-			if !exists {
-				return fmt.Errorf("Template '%v' has a dependency to template '%v', but that template cannot be found", ct.ID, ct.Parent)
+			if _, exists := s.Get(ct.Parent); !exists {
+				markBroken(ct, yaml.NewPathError(fmt.Errorf("Has a dependency to template '%v', but that template cannot be found", ct.Parent), "parent"))
+				continue
 			}
-
-			if err = parentCt.addLayoutChild(ct); err != nil {
-				return err
+			if chain := s.findCycle(ct, func(ct *Chronicle) string { return ct.Parent }); chain != nil {
+				markBroken(ct, yaml.NewPathError(fmt.Errorf("Found cyclic inheritance dependencies. Inheritance chain is %v", chain), "parent"))
+				continue
 			}
 		}
 
 		if utils.IsSet(ct.DisplayParent) {
-			parentCt, exists := s.Get(ct.DisplayParent)
-			if !exists {
-				return fmt.Errorf("Template '%v' has a dependency to template '%v', but that template cannot be found", ct.ID, ct.Parent)
+			if _, exists := s.Get(ct.DisplayParent); !exists {
+				markBroken(ct, yaml.NewPathError(fmt.Errorf("Has a display dependency to template '%v', but that template cannot be found", ct.DisplayParent), "displayparent"))
+				continue
+			}
+			if chain := s.findCycle(ct, func(ct *Chronicle) string { return ct.DisplayParent }); chain != nil {
+				markBroken(ct, yaml.NewPathError(fmt.Errorf("Found cyclic display dependencies. Dependency chain is %v", chain), "displayparent"))
+				continue
 			}
-			ct.displayParent = parentCt
 		}
 	}
 
-	// inherit and resolve, starting at root nodes
-	err = s.performPreOrderLayout(func(ct *Chronicle) error {
+	// add layout children. Cycles were already checked above, so the only templates that
+	// cannot be reached from a root template are those that depend on a broken template.
+	for _, ct := range s.getSortedList() {
+		if utils.IsSet(ct.Parent) && !broken[ct.ID] {
+			parentCt, _ := s.Get(ct.Parent)
+			utils.AssertNoError(parentCt.addLayoutChild(ct))
+		}
+	}
+
+	markChildrenBroken := func(ct *Chronicle) {
+		for _, childCt := range ct.layoutChildren {
+			if !broken[childCt.ID] {
+				markBroken(childCt, yaml.NewPathError(fmt.Errorf("Depends on invalid template '%v'", ct.ID), "parent"))
+			}
+		}
+	}
+
+	// inherit and resolve, starting at root nodes. As broken templates were not added as
+	// layout children above, they are root nodes as well and each template is visited.
+	s.performPreOrderLayout(func(ct *Chronicle) error {
+		if broken[ct.ID] {
+			markChildrenBroken(ct)
+			return nil
+		}
+
 		for _, childCt := range ct.layoutChildren {
 			// ensure that children inherit before the current chronicle is resolved
-			if err = childCt.inheritFrom(ct); err != nil {
-				return err
+			if err := childCt.inheritFrom(ct); err != nil {
+				markBroken(childCt, err)
 			}
 		}
 
-		// resolve each chronicle template
-		if err = ct.resolve(); err != nil {
-			return err
+		// resolve and validate each chronicle template. Entries that could not be
+		// resolved are not validated, as otherwise the same issue might show up twice.
+		errs := ct.resolveAll()
+		for _, err := range ct.validateAll() {
+			if !hasSameLocationAsAny(err, errs) {
+				errs = append(errs, err)
+			}
+		}
+		for _, err := range errs {
+			markBroken(ct, err)
+		}
+
+		if broken[ct.ID] {
+			markChildrenBroken(ct)
 		}
 		return nil
 	})
-	if err != nil {
-		return err
+
+	// remove broken templates from the store and from the children lists
+	for id := range broken {
+		delete(s.templates, id)
+	}
+	for _, ct := range s.templates {
+		ct.layoutChildren = s.filterContained(ct.layoutChildren)
+	}
+
+	// set display parents. If a display parent was removed due to problems, then
+	// the template is shown on the top level instead.
+	for _, ct := range s.templates {
+		if utils.IsSet(ct.DisplayParent) {
+			ct.displayParent, _ = s.Get(ct.DisplayParent)
+		}
 	}
 
 	// now every node has its display parent set, but including hidden nodes.
 	// iterate over all nodes and cut hidden nodes out
-	for _, ct := range *s {
+	for _, ct := range s.templates {
 		parentNode := ct.displayParent
 		for parentNode != nil && parentNode.isHidden() {
 			parentNode = parentNode.displayParent
@@ -132,42 +412,80 @@ func (s *Store) resolve() (err error) {
 	// now fill the display children list of all nodes.
 	// ignore hidden nodes, as they should be cut out of the hierarchie and we want to avoid
 	// that they appear in any children lists
-	for _, ct := range *s {
+	for _, ct := range s.getSortedList() {
 		if ct.isHidden() {
 			continue
 		}
 
 		parentNode := ct.displayParent
 		if parentNode != nil {
-			if err = parentNode.addDisplayChild(ct); err != nil {
-				return err
-			}
+			utils.AssertNoError(parentNode.addDisplayChild(ct))
 		}
 	}
 
-	return nil
+	return problems
 }
 
-func (s *Store) isValid() (err error) {
-	// get deterministic template order for validation. The order itself is not relevant
-	// for the validation. But if a parent template has invalid entries, then the error
-	// message should referr to that template, not to some other template that inherits it.
-	// Basically this function guarantees that parent templates are validated before their
-	// child templates.
+// hasSameLocationAsAny checks whether the provided error points to the same location
+// within a template file as one of the errors from the provided list.
+func hasSameLocationAsAny(err error, others []error) bool {
+	pe, ok := err.(*yaml.PathError)
+	if !ok {
+		return false
+	}
 
-	if err = s.performPreOrderLayout(func(ct *Chronicle) error {
-		if err = ct.IsValid(); err != nil {
-			return err
+	for _, other := range others {
+		if ope, ok := other.(*yaml.PathError); ok && strings.Join(ope.Path, "/") == strings.Join(pe.Path, "/") {
+			return true
 		}
-
-		return nil
-	}); err != nil {
-		return err
 	}
+	return false
+}
 
+// findCycle follows the parent IDs returned by the provided function, starting at the
+// provided template. If the starting template is part of a cycle, then the chain of IDs
+// forming the cycle is returned. Otherwise nil is returned.
+func (s *Store) findCycle(startCt *Chronicle, getParentID func(*Chronicle) string) (chain []string) {
+	chain = []string{startCt.ID}
+	for curCt := startCt; utils.IsSet(getParentID(curCt)); {
+		parentID := getParentID(curCt)
+		if parentID == startCt.ID {
+			return append(chain, parentID) // add entry again to have complete cycle in output
+		}
+		if utils.Contains(chain, parentID) {
+			return nil // cycle further up in the hierarchy, but not including the start template
+		}
+		chain = append(chain, parentID)
+
+		var exists bool
+		if curCt, exists = s.Get(parentID); !exists {
+			return nil
+		}
+	}
 	return nil
 }
 
+// filterContained returns only those templates from the provided list that are contained in the store.
+func (s *Store) filterContained(cts []*Chronicle) (result []*Chronicle) {
+	result = make([]*Chronicle, 0, len(cts))
+	for _, ct := range cts {
+		if _, exists := s.Get(ct.ID); exists {
+			result = append(result, ct)
+		}
+	}
+	return result
+}
+
+// getSortedList returns all templates contained in the store, sorted by ID.
+func (s *Store) getSortedList() (result []*Chronicle) {
+	result = make([]*Chronicle, 0, len(s.templates))
+	for _, ct := range s.templates {
+		result = append(result, ct)
+	}
+	sortChronicleList(result)
+	return result
+}
+
 func (s *Store) performPreOrderLayout(workerFct func(*Chronicle) error) error {
 	return s.performPreOrderGeneric(workerFct, func(ct *Chronicle) []*Chronicle { return ct.layoutChildren })
 }
@@ -190,7 +508,7 @@ func (s *Store) performPreOrderGeneric(workerFct func(*Chronicle) error, getChil
 func (s *Store) getRootTemplates() (result []*Chronicle) {
 	result = make([]*Chronicle, 0)
 
-	for _, ct := range *s {
+	for _, ct := range s.templates {
 		if ct.layoutParent == nil {
 			result = append(result, ct)
 		}
@@ -203,7 +521,7 @@ func (s *Store) getRootTemplates() (result []*Chronicle) {
 func (s *Store) getTemplatesInheritingFrom(parentID string) (childIDs []string) {
 	childIDs = make([]string, 0)
 
-	for key, template := range *s {
+	for key, template := range s.templates {
 		if (!utils.IsSet(parentID) && !utils.IsSet(template.Parent)) ||
 			(template.Parent == parentID) {
 			childIDs = append(childIDs, key)
@@ -247,7 +565,7 @@ func (s *Store) SearchForTemplates(keywords ...string) (result string, foundMatc
 
 	var sb strings.Builder
 	foundSomething := false
-	for key, template := range *s {
+	for key, template := range s.templates {
 		if termsContainAllKeywords(strings.ToLower(key), strings.ToLower(template.Description), lowerKW...) {
 			foundSomething = true
 			fmt.Fprintf(&sb, "- %v: %v\n", template.ID, template.Description)
@@ -1 +1,210 @@
 package template
+
+import (
+	"path/filepath"
+	"testing"
+
+	test "github.com/Blesmol/pfscf/pfscf/testutils"
+)
+
+func getValidationTestDir(subdir string) string {
+	return filepath.Join(chronicleTemplateTestDir, "Validation", subdir)
+}
+
+func TestStore_loadStoreForDirs(t *testing.T) {
+	t.Run("errors", func(t *testing.T) {
+		t.Run("non-existing dir", func(t *testing.T) {
+			_, _, err := loadStoreForDirs(getValidationTestDir("doesNotExist"))
+			test.ExpectError(t, err)
+		})
+	})
+
+	t.Run("valid", func(t *testing.T) {
+		t.Run("no problems", func(t *testing.T) {
+			store, problems, err := loadStoreForDirs(getValidationTestDir("valid"))
+			test.ExpectNoError(t, err)
+			test.ExpectEqual(t, len(problems), 0)
+			test.ExpectEqual(t, len(store.templates), 2)
+
+			child, exists := store.Get("child")
+			test.ExpectTrue(t, exists)
+			test.ExpectEqual(t, len(child.Parameters), 1)
+		})
+
+		t.Run("all problems are collected", func(t *testing.T) {
+			store, problems, err := loadStoreForDirs(getValidationTestDir("problems"))
+			test.ExpectNoError(t, err)
+
+			expected := []struct {
+				file   string
+				line   int
+				id     string
+				errMsg string
+			}{
+				{"cyclic1.yml", 3, "cyclic1", "Found cyclic inheritance dependencies"},
+				{"cyclic2.yml", 3, "cyclic2", "Found cyclic inheritance dependencies"},
+				{"cyclicChild.yml", 3, "cyclicChild", "Depends on invalid template 'cyclic1'"},
+				{"duplicate1.yml", 1, "duplicate", "Found multiple templates with ID 'duplicate'"},
+				{"duplicate2.yml", 1, "duplicate", "Found multiple templates with ID 'duplicate'"},
+				{"invalidChild.yml", 3, "invalidChild", "Depends on invalid template 'invalidContent'"},
+				{"invalidContent.yml", 16, "invalidContent", "Cyclic dependency"},
+				{"invalidContent.yml", 20, "invalidContent", "Contradicting values for field 'font'"},
+				{"invalidContent.yml", 25, "invalidContent", "out of range"},
+				{"invalidContent.yml", 31, "invalidContent", "Canvas 'doesNotExist' does not exist"},
+				{"malformed.yml", 3, "", "mapping values are not allowed"},
+				{"missingId.yml", 0, "", "Missing template ID"},
+				{"nonExistingParent.yml", 3, "nonExistingParent", "template 'doesNotExist', but that template cannot be found"},
+			}
+
+			test.ExpectEqual(t, len(problems), len(expected))
+			for idx, exp := range expected {
+				if idx >= len(problems) {
+					break
+				}
+				test.ExpectEqual(t, filepath.Base(problems[idx].Filename), exp.file)
+				test.ExpectEqual(t, problems[idx].Line, exp.line)
+				test.ExpectEqual(t, problems[idx].TemplateID, exp.id)
+				test.ExpectError(t, problems[idx].Err, exp.errMsg)
+			}
+
+			// only the valid template should remain
+			test.ExpectEqual(t, len(store.templates), 1)
+			_, exists := store.Get("valid")
+			test.ExpectTrue(t, exists)
+		})
+	})
+}
+
+func TestStore_validateDirs(t *testing.T) {
+	t.Run("errors", func(t *testing.T) {
+		t.Run("unknown template", func(t *testing.T) {
+			_, err := validateDirs([]string{getValidationTestDir("problems")}, "doesNotExist")
+			test.ExpectError(t, err, "Cannot find template 'doesNotExist'", "2 template file(s) could not be read")
+		})
+	})
+
+	t.Run("valid", func(t *testing.T) {
+		t.Run("restricted to template IDs", func(t *testing.T) {
+			problems, err := validateDirs([]string{getValidationTestDir("problems")}, "invalidContent", "valid")
+			test.ExpectNoError(t, err)
+			test.ExpectEqual(t, len(problems), 4)
+			for _, problem := range problems {
+				test.ExpectEqual(t, problem.TemplateID, "invalidContent")
+			}
+		})
+
+		t.Run("template without problems", func(t *testing.T) {
+			problems, err := validateDirs([]string{getValidationTestDir("valid")}, "child")
+			test.ExpectNoError(t, err)
+			test.ExpectEqual(t, len(problems), 0)
+		})
+	})
+}
+
+func TestStore_getStoreForDirs(t *testing.T) {
+	t.Run("errors", func(t *testing.T) {
+		t.Run("non-existing dir", func(t *testing.T) {
+			store, err := getStoreForDirs(getValidationTestDir("doesNotExist"))
+			test.ExpectError(t, err)
+			test.ExpectNil(t, store)
+		})
+	})
+
+	t.Run("valid", func(t *testing.T) {
+		t.Run("broken templates are skipped", func(t *testing.T) {
+			store, err := getStoreForDirs(getValidationTestDir("problems"))
+			test.ExpectNoError(t, err)
+			test.ExpectEqual(t, len(store.Problems()), 13)
+
+			ct, exists := store.Get("valid")
+			test.ExpectTrue(t, exists)
+			test.ExpectNotNil(t, ct)
+
+			_, exists = store.Get("invalidChild")
+			test.ExpectFalse(t, exists)
+			test.ExpectEqual(t, len(store.GetProblemsFor("invalidChild")), 1)
+			test.ExpectEqual(t, len(store.GetProblemsFor("valid")), 0)
+
+			test.ExpectStringContains(t, store.ListTemplates(), "- valid: some description")
+			test.ExpectStringContainsNot(t, store.ListTemplates(), "invalidChild")
+		})
+	})
+}
+
+func TestStore_Select(t *testing.T) {
+	store, err := getStoreForDirs(getValidationTestDir("problems"))
+	test.ExpectNoError(t, err)
+
+	t.Run("errors", func(t *testing.T) {
+		t.Run("unknown template", func(t *testing.T) {
+			ct, err := store.Select("doesNotExist")
+			test.ExpectError(t, err, "Cannot find template 'doesNotExist'", "2 template file(s) could not be read")
+			test.ExpectNil(t, ct)
+		})
+
+		t.Run("broken template", func(t *testing.T) {
+			ct, err := store.Select("invalidContent")
+			test.ExpectError(t, err, "Template 'invalidContent' cannot be used", "invalidContent.yml:31", "Canvas 'doesNotExist' does not exist")
+			test.ExpectNil(t, ct)
+		})
+	})
+
+	t.Run("valid", func(t *testing.T) {
+		ct, err := store.Select("valid")
+		test.ExpectNoError(t, err)
+		test.ExpectEqual(t, ct.ID, "valid")
+	})
+}
+
+func TestStore_overrides(t *testing.T) {
+	t.Run("template from later dir wins", func(t *testing.T) {
+		store, err := getStoreForDirs(getValidationTestDir("valid"), getValidationTestDir("override"))
+		test.ExpectNoError(t, err)
+		test.ExpectEqual(t, len(store.Problems()), 0)
+		test.ExpectEqual(t, len(store.templates), 3)
+
+		parent, _ := store.Get("parent")
+		test.ExpectEqual(t, parent.Description, "Overriding parent template")
+
+		// children from the lower-priority dir inherit from the overriding template
+		house, exists := store.Get("house")
+		test.ExpectTrue(t, exists)
+		test.ExpectEqual(t, len(house.Parameters), 2)
+
+		overrides := store.Overrides()
+		test.ExpectEqual(t, len(overrides), 1)
+		test.ExpectEqual(t, overrides[0].TemplateID, "parent")
+		test.ExpectEqual(t, overrides[0].Filename, filepath.Join(getValidationTestDir("override"), "parent.yml"))
+		test.ExpectEqual(t, len(overrides[0].Overridden), 1)
+		test.ExpectEqual(t, overrides[0].Overridden[0], filepath.Join(getValidationTestDir("valid"), "parent.yml"))
+	})
+
+	t.Run("order of dirs matters", func(t *testing.T) {
+		store, err := getStoreForDirs(getValidationTestDir("override"), getValidationTestDir("valid"))
+		test.ExpectNoError(t, err)
+
+		parent, _ := store.Get("parent")
+		test.ExpectEqual(t, parent.Description, "Parent template")
+		test.ExpectEqual(t, store.Overrides()[0].Filename, filepath.Join(getValidationTestDir("valid"), "parent.yml"))
+	})
+
+	t.Run("duplicates hide templates from lower-priority dirs", func(t *testing.T) {
+		store, err := getStoreForDirs(getValidationTestDir("hidden"), getValidationTestDir("problems"))
+		test.ExpectNoError(t, err)
+
+		valid, exists := store.Get("valid")
+		test.ExpectTrue(t, exists)
+		test.ExpectEqual(t, valid.filename, filepath.Join(getValidationTestDir("problems"), "valid.yml"))
+
+		// duplicate IDs within the higher-priority dir also hide the template from the lower-priority dir
+		_, exists = store.Get("duplicate")
+		test.ExpectFalse(t, exists)
+
+		overrides := store.Overrides()
+		test.ExpectEqual(t, len(overrides), 1)
+		test.ExpectEqual(t, overrides[0].TemplateID, "valid")
+		test.ExpectEqual(t, overrides[0].Filename, filepath.Join(getValidationTestDir("problems"), "valid.yml"))
+		test.ExpectEqual(t, len(overrides[0].Overridden), 1)
+		test.ExpectEqual(t, overrides[0].Overridden[0], filepath.Join(getValidationTestDir("hidden"), "valid.yml"))
+	})
+}
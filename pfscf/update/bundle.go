@@ -0,0 +1,266 @@
+package update
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+const (
+	// downloadTimeout is the maximum time for downloading a bundle, so that a stalled
+	// server does not block the update forever
+	downloadTimeout = 2 * time.Minute
+)
+
+var (
+	downloadClient = &http.Client{Timeout: downloadTimeout}
+)
+
+// isURL checks whether the provided source is an HTTP(S) URL.
+func isURL(source string) bool {
+	u, err := url.Parse(source)
+	return err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != ""
+}
+
+// download fetches the file at the provided URL and stores it in the provided
+// directory. The name of the stored file is returned.
+func download(source, dir string) (filename string, err error) {
+	resp, err := downloadClient.Get(source)
+	if err != nil {
+		return "", fmt.Errorf("Error downloading '%v': %v", source, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("Error downloading '%v': %v", source, resp.Status)
+	}
+
+	u, _ := url.Parse(source) // already checked by isURL()
+	filename = filepath.Join(dir, "bundle"+getBundleExtension(path.Base(u.Path)))
+
+	file, err := os.Create(filename)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	if _, err = io.Copy(file, resp.Body); err != nil {
+		return "", fmt.Errorf("Error downloading '%v': %v", source, err)
+	}
+
+	return filename, nil
+}
+
+// getBundleExtension returns the supported archive extension of the provided
+// filename, or an empty string if the extension is not supported.
+func getBundleExtension(filename string) (ext string) {
+	lowerName := strings.ToLower(filename)
+	for _, ext = range []string{".tar.gz", ".tgz", ".tar", ".zip"} {
+		if strings.HasSuffix(lowerName, ext) {
+			return ext
+		}
+	}
+	return ""
+}
+
+// unpack extracts or copies the bundle from the provided local path, which can be
+// either a directory or an archive file, into the provided target directory.
+func unpack(bundle, targetDir string) (err error) {
+	info, err := os.Stat(bundle)
+	if err != nil {
+		return fmt.Errorf("Cannot access bundle: %v", err)
+	}
+
+	if info.IsDir() {
+		return copyDir(bundle, targetDir)
+	}
+
+	switch getBundleExtension(bundle) {
+	case ".zip":
+		return extractZip(bundle, targetDir)
+	case ".tar":
+		return extractTar(bundle, targetDir, false)
+	case ".tar.gz", ".tgz":
+		return extractTar(bundle, targetDir, true)
+	default:
+		return fmt.Errorf("Unsupported bundle format for file '%v', expected a directory or a .zip, .tar, .tar.gz or .tgz file", bundle)
+	}
+}
+
+// getTargetPath returns the location where an archive entry with the provided
+// name should be stored. Entries that would be stored outside of the target
+// directory are refused.
+func getTargetPath(targetDir, name string) (targetPath string, err error) {
+	if !isSafeRelativePath(name) {
+		return "", fmt.Errorf("Bundle contains invalid path '%v'", name)
+	}
+	return filepath.Join(targetDir, filepath.FromSlash(path.Clean(name))), nil
+}
+
+func extractZip(bundle, targetDir string) (err error) {
+	reader, err := zip.OpenReader(bundle)
+	if err != nil {
+		return fmt.Errorf("Error opening zip file: %v", err)
+	}
+	defer reader.Close()
+
+	for _, zipFile := range reader.File {
+		targetPath, err := getTargetPath(targetDir, zipFile.Name)
+		if err != nil {
+			return err
+		}
+
+		if zipFile.FileInfo().IsDir() {
+			if err = os.MkdirAll(targetPath, 0755); err != nil {
+				return err
+			}
+			continue
+		}
+		if !zipFile.Mode().IsRegular() {
+			return fmt.Errorf("Bundle contains unsupported entry '%v'", zipFile.Name)
+		}
+
+		content, err := zipFile.Open()
+		if err != nil {
+			return fmt.Errorf("Error reading '%v' from zip file: %v", zipFile.Name, err)
+		}
+		err = writeFile(targetPath, content)
+		content.Close()
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func extractTar(bundle string, targetDir string, gzipped bool) (err error) {
+	file, err := os.Open(bundle)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	var input io.Reader = file
+	if gzipped {
+		gzipReader, err := gzip.NewReader(file)
+		if err != nil {
+			return fmt.Errorf("Error opening gzip file: %v", err)
+		}
+		defer gzipReader.Close()
+		input = gzipReader
+	}
+
+	reader := tar.NewReader(input)
+	for {
+		header, err := reader.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("Error reading tar file: %v", err)
+		}
+
+		switch header.Typeflag {
+		case tar.TypeXGlobalHeader:
+			continue // e.g. created by "git archive", contains no file
+		case tar.TypeDir:
+			targetPath, err := getTargetPath(targetDir, header.Name)
+			if err != nil {
+				return err
+			}
+			if err = os.MkdirAll(targetPath, 0755); err != nil {
+				return err
+			}
+		case tar.TypeReg, tar.TypeRegA:
+			targetPath, err := getTargetPath(targetDir, header.Name)
+			if err != nil {
+				return err
+			}
+			if err = writeFile(targetPath, reader); err != nil {
+				return err
+			}
+		default:
+			return fmt.Errorf("Bundle contains unsupported entry '%v'", header.Name)
+		}
+	}
+}
+
+func copyDir(sourceDir, targetDir string) (err error) {
+	return filepath.Walk(sourceDir, func(filename string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		relPath, err := filepath.Rel(sourceDir, filename)
+		if err != nil {
+			return err
+		}
+		targetPath := filepath.Join(targetDir, relPath)
+
+		if info.IsDir() {
+			if info.Name() == ".git" {
+				return filepath.SkipDir
+			}
+			return os.MkdirAll(targetPath, 0755)
+		}
+		if !info.Mode().IsRegular() {
+			return fmt.Errorf("Bundle contains unsupported file '%v'", filename)
+		}
+
+		file, err := os.Open(filename)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		return writeFile(targetPath, file)
+	})
+}
+
+// writeFile stores the provided content in a new file, creating parent directories as necessary.
+func writeFile(filename string, content io.Reader) (err error) {
+	if err = os.MkdirAll(filepath.Dir(filename), 0755); err != nil {
+		return err
+	}
+
+	file, err := os.OpenFile(filename, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	_, err = io.Copy(file, content)
+	return err
+}
+
+// findBundleRoot returns the directory containing the manifest file. This is either
+// the provided directory itself or, as e.g. created by "git archive --prefix", its only
+// subdirectory.
+func findBundleRoot(dir string) (root string, err error) {
+	if _, err = os.Stat(filepath.Join(dir, ManifestFilename)); err == nil {
+		return dir, nil
+	}
+
+	entries, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return "", err
+	}
+	if len(entries) == 1 && entries[0].IsDir() {
+		subDir := filepath.Join(dir, entries[0].Name())
+		if _, err = os.Stat(filepath.Join(subDir, ManifestFilename)); err == nil {
+			return subDir, nil
+		}
+	}
+
+	return "", fmt.Errorf("Bundle does not contain a manifest file '%v'", ManifestFilename)
+}
@@ -0,0 +1,157 @@
+package update
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+const (
+	// ManifestFilename is the name of the checksum manifest that has to be
+	// contained in the root directory of each template bundle.
+	ManifestFilename = "checksums.sha256"
+)
+
+// manifest maps relative, slash-separated file paths to their expected sha256 checksum.
+type manifest map[string]string
+
+// readManifest reads a checksum manifest. The format is the same as the output of
+// `sha256sum`, i.e. each line contains the hex-encoded checksum followed by two
+// spaces (or a space and an asterisk) and the relative path of the file.
+func readManifest(filename string) (m manifest, err error) {
+	file, err := os.Open(filename)
+	if err != nil {
+		return nil, fmt.Errorf("Error reading manifest: %v", err)
+	}
+	defer file.Close()
+
+	m = make(manifest)
+	scanner := bufio.NewScanner(file)
+	for lineNo := 1; scanner.Scan(); lineNo++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		fields := strings.SplitN(line, " ", 2)
+		if len(fields) != 2 {
+			return nil, fmt.Errorf("Manifest line %d: Expected checksum and filename", lineNo)
+		}
+		checksum := strings.ToLower(fields[0])
+		filePath := strings.TrimLeft(fields[1], " *")
+
+		if decoded, err := hex.DecodeString(checksum); err != nil || len(decoded) != sha256.Size {
+			return nil, fmt.Errorf("Manifest line %d: Invalid sha256 checksum '%v'", lineNo, fields[0])
+		}
+		if !isSafeRelativePath(filePath) {
+			return nil, fmt.Errorf("Manifest line %d: Invalid file path '%v'", lineNo, filePath)
+		}
+		filePath = path.Clean(filePath)
+		if _, exists := m[filePath]; exists {
+			return nil, fmt.Errorf("Manifest line %d: Duplicate entry for file '%v'", lineNo, filePath)
+		}
+
+		m[filePath] = checksum
+	}
+	if err = scanner.Err(); err != nil {
+		return nil, fmt.Errorf("Error reading manifest: %v", err)
+	}
+
+	if len(m) == 0 {
+		return nil, fmt.Errorf("Manifest does not contain any entries")
+	}
+
+	return m, nil
+}
+
+// verify checks that the provided directory contains exactly the files listed in the
+// manifest, apart from the manifest itself, and that all checksums match.
+func (m manifest) verify(dir string) (err error) {
+	found := make(map[string]bool)
+
+	err = filepath.Walk(dir, func(filename string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			return nil
+		}
+		if !info.Mode().IsRegular() {
+			return fmt.Errorf("Bundle contains unsupported file '%v'", filename)
+		}
+
+		relPath, err := filepath.Rel(dir, filename)
+		if err != nil {
+			return err
+		}
+		relPath = filepath.ToSlash(relPath)
+		if relPath == ManifestFilename {
+			return nil
+		}
+
+		expChecksum, exists := m[relPath]
+		if !exists {
+			return fmt.Errorf("File '%v' is not listed in the manifest", relPath)
+		}
+
+		checksum, err := getChecksum(filename)
+		if err != nil {
+			return err
+		}
+		if checksum != expChecksum {
+			return fmt.Errorf("Checksum mismatch for file '%v'", relPath)
+		}
+
+		found[relPath] = true
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	missing := make([]string, 0)
+	for relPath := range m {
+		if !found[relPath] {
+			missing = append(missing, relPath)
+		}
+	}
+	if len(missing) > 0 {
+		sort.Strings(missing)
+		return fmt.Errorf("Files listed in the manifest are missing from the bundle: %v", missing)
+	}
+
+	return nil
+}
+
+// getChecksum returns the hex-encoded sha256 checksum of the provided file.
+func getChecksum(filename string) (checksum string, err error) {
+	file, err := os.Open(filename)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	hash := sha256.New()
+	if _, err = io.Copy(hash, file); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// isSafeRelativePath checks that the provided slash-separated path is relative and
+// does not point outside of the directory it is relative to.
+func isSafeRelativePath(p string) bool {
+	if p == "" || path.IsAbs(p) || filepath.IsAbs(p) || strings.Contains(p, `\`) {
+		return false
+	}
+	cleaned := path.Clean(p)
+	return cleaned != "." && cleaned != ".." && !strings.HasPrefix(cleaned, "../")
+}
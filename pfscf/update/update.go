@@ -0,0 +1,190 @@
+// Package update provides means to install template bundles into the local
+// template directory.
+package update
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+)
+
+const (
+	previousDirSuffix = ".previous"
+)
+
+var (
+	// rename moves files and directories, can be replaced in tests to simulate errors
+	rename = os.Rename
+)
+
+// GetPreviousDir returns the directory in which the previously installed templates
+// are kept after an update.
+func GetPreviousDir(targetDir string) (dir string) {
+	return filepath.Clean(targetDir) + previousDirSuffix
+}
+
+// HasPreviousVersion checks whether previously installed templates are available
+// for the provided target directory.
+func HasPreviousVersion(targetDir string) bool {
+	return dirExists(GetPreviousDir(targetDir))
+}
+
+// Update retrieves the template bundle from the provided source, verifies it against
+// the contained checksum manifest and then replaces the content of the target directory
+// with the content of the bundle. The source can either be a local path or an HTTP(S) URL,
+// and the bundle can either be a directory or a .zip, .tar, .tar.gz or .tgz file.
+// The previous content of the target directory is kept, see Rollback().
+// Returns the number of files that were installed.
+func Update(source, targetDir string) (numFiles int, err error) {
+	targetDir = filepath.Clean(targetDir)
+
+	// the working dir is created next to the target dir so that the final
+	// rename operations are performed on the same file system
+	workDir, err := ioutil.TempDir(filepath.Dir(targetDir), "."+filepath.Base(targetDir)+".update-")
+	if err != nil {
+		return 0, fmt.Errorf("Error creating working directory: %v", err)
+	}
+	// the working dir is kept if it still contains the previous templates after an error
+	keepWorkDir := false
+	defer func() {
+		if !keepWorkDir {
+			os.RemoveAll(workDir)
+		}
+	}()
+
+	bundle := source
+	if isURL(source) {
+		if bundle, err = download(source, workDir); err != nil {
+			return 0, err
+		}
+	}
+
+	unpackDir := filepath.Join(workDir, "unpacked")
+	if err = unpack(bundle, unpackDir); err != nil {
+		return 0, err
+	}
+
+	bundleRoot, err := findBundleRoot(unpackDir)
+	if err != nil {
+		return 0, err
+	}
+	m, err := readManifest(filepath.Join(bundleRoot, ManifestFilename))
+	if err != nil {
+		return 0, err
+	}
+	if err = m.verify(bundleRoot); err != nil {
+		return 0, fmt.Errorf("Error verifying bundle: %v", err)
+	}
+
+	if keepWorkDir, err = install(bundleRoot, targetDir, workDir); err != nil {
+		return 0, err
+	}
+
+	return len(m), nil
+}
+
+// install moves the new directory to the target location and keeps the current
+// content of the target location as previous version. The previous version from an
+// earlier update is moved to the provided working dir, so that it is removed
+// together with it. In case of errors, the original state is restored. If this is
+// not possible, keepWorkDir reports whether the working dir still contains the
+// previous templates and thus must not be removed.
+func install(newDir, targetDir, workDir string) (keepWorkDir bool, err error) {
+	prevDir := GetPreviousDir(targetDir)
+	oldPrevDir := filepath.Join(workDir, "previous")
+
+	if dirExists(prevDir) {
+		if err = rename(prevDir, oldPrevDir); err != nil {
+			return false, fmt.Errorf("Error removing previous templates: %v", err)
+		}
+	}
+
+	if dirExists(targetDir) {
+		if err = rename(targetDir, prevDir); err != nil {
+			return restorePrevious(oldPrevDir, prevDir, fmt.Errorf("Error moving current templates: %v", err))
+		}
+	}
+
+	if err = rename(newDir, targetDir); err != nil {
+		err = fmt.Errorf("Error installing new templates: %v", err)
+		if dirExists(prevDir) {
+			if restoreErr := rename(prevDir, targetDir); restoreErr != nil {
+				err = fmt.Errorf("%v. The current templates could not be moved back and were kept in '%v'", err, prevDir)
+				if dirExists(oldPrevDir) {
+					return true, fmt.Errorf("%v. The previous templates were kept in '%v'", err, oldPrevDir)
+				}
+				return false, err
+			}
+		}
+		return restorePrevious(oldPrevDir, prevDir, err)
+	}
+
+	return false, nil
+}
+
+// restorePrevious moves the previous templates from an earlier update back after
+// the provided error occurred. Returns whether they are still in the working dir.
+func restorePrevious(oldPrevDir, prevDir string, err error) (bool, error) {
+	if dirExists(oldPrevDir) {
+		if restoreErr := rename(oldPrevDir, prevDir); restoreErr != nil {
+			return true, fmt.Errorf("%v. The previous templates could not be moved back and were kept in '%v'", err, oldPrevDir)
+		}
+	}
+	return false, err
+}
+
+// Rollback restores the templates that were installed before the last update.
+// The replaced templates are kept as previous version in turn, so that a
+// rollback can be reverted by calling Rollback() again.
+func Rollback(targetDir string) (err error) {
+	targetDir = filepath.Clean(targetDir)
+	prevDir := GetPreviousDir(targetDir)
+
+	if !dirExists(prevDir) {
+		return fmt.Errorf("No previous templates found in '%v'", prevDir)
+	}
+
+	workDir, err := ioutil.TempDir(filepath.Dir(targetDir), "."+filepath.Base(targetDir)+".rollback-")
+	if err != nil {
+		return fmt.Errorf("Error creating working directory: %v", err)
+	}
+	// the working dir is kept if it still contains the current templates after an error
+	keepWorkDir := false
+	defer func() {
+		if !keepWorkDir {
+			os.RemoveAll(workDir)
+		}
+	}()
+
+	currentDir := filepath.Join(workDir, "current")
+	if dirExists(targetDir) {
+		if err = rename(targetDir, currentDir); err != nil {
+			return fmt.Errorf("Error moving current templates: %v", err)
+		}
+	}
+
+	if err = rename(prevDir, targetDir); err != nil {
+		if dirExists(currentDir) {
+			if restoreErr := rename(currentDir, targetDir); restoreErr != nil {
+				keepWorkDir = true
+				return fmt.Errorf("Error restoring previous templates: %v. The current templates could not be moved back and were kept in '%v'", err, currentDir)
+			}
+		}
+		return fmt.Errorf("Error restoring previous templates: %v", err)
+	}
+
+	if dirExists(currentDir) {
+		if err = rename(currentDir, prevDir); err != nil {
+			keepWorkDir = true
+			return fmt.Errorf("Error keeping replaced templates: %v. They were kept in '%v'", err, currentDir)
+		}
+	}
+
+	return nil
+}
+
+func dirExists(dir string) bool {
+	info, err := os.Stat(dir)
+	return err == nil && info.IsDir()
+}
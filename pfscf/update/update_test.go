@@ -0,0 +1,359 @@
+package update
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	test "github.com/Blesmol/pfscf/pfscf/testutils"
+	"github.com/Blesmol/pfscf/pfscf/utils"
+)
+
+var (
+	updateTestDir string
+)
+
+func init() {
+	utils.SetIsTestEnvironment(true)
+	updateTestDir = filepath.Join(utils.GetExecutableDir(), "testdata")
+}
+
+// archiveEntry describes a single file to be put into a test archive
+type archiveEntry struct {
+	name    string
+	content string
+}
+
+func getBundleEntries(t *testing.T, prefix string) (entries []archiveEntry) {
+	for _, name := range []string{ManifestFilename, "foo.yml", "pfs2/bar.yml"} {
+		content, err := ioutil.ReadFile(filepath.Join(updateTestDir, "bundle", filepath.FromSlash(name)))
+		test.ExpectNoError(t, err)
+		entries = append(entries, archiveEntry{prefix + name, string(content)})
+	}
+	return entries
+}
+
+func writeZip(t *testing.T, filename string, entries []archiveEntry) {
+	file, err := os.Create(filename)
+	test.ExpectNoError(t, err)
+	defer file.Close()
+
+	writer := zip.NewWriter(file)
+	for _, entry := range entries {
+		w, err := writer.Create(entry.name)
+		test.ExpectNoError(t, err)
+		_, err = io.WriteString(w, entry.content)
+		test.ExpectNoError(t, err)
+	}
+	test.ExpectNoError(t, writer.Close())
+}
+
+func writeTar(t *testing.T, filename string, gzipped bool, entries []archiveEntry) {
+	file, err := os.Create(filename)
+	test.ExpectNoError(t, err)
+	defer file.Close()
+
+	var output io.Writer = file
+	if gzipped {
+		gzipWriter := gzip.NewWriter(file)
+		defer gzipWriter.Close()
+		output = gzipWriter
+	}
+
+	writer := tar.NewWriter(output)
+	for _, entry := range entries {
+		header := &tar.Header{Name: entry.name, Mode: 0644, Size: int64(len(entry.content)), Typeflag: tar.TypeReg}
+		test.ExpectNoError(t, writer.WriteHeader(header))
+		_, err = io.WriteString(writer, entry.content)
+		test.ExpectNoError(t, err)
+	}
+	test.ExpectNoError(t, writer.Close())
+}
+
+func expectInstalledBundle(t *testing.T, targetDir string) {
+	test.ExpectFileExists(t, filepath.Join(targetDir, "foo.yml"))
+	test.ExpectFileExists(t, filepath.Join(targetDir, "pfs2", "bar.yml"))
+	test.ExpectFileExists(t, filepath.Join(targetDir, ManifestFilename))
+}
+
+// expectNoLeftovers checks that no temporary working directories remain next to the target dir
+func expectNoLeftovers(t *testing.T, workDir string) {
+	entries, err := ioutil.ReadDir(workDir)
+	test.ExpectNoError(t, err)
+	for _, entry := range entries {
+		test.ExpectNotEqual(t, entry.Name()[0], byte('.'))
+	}
+}
+
+func TestUpdate(t *testing.T) {
+	t.Run("errors", func(t *testing.T) {
+		for _, tc := range []struct {
+			bundle string
+			errMsg string
+		}{
+			{"doesNotExist", "Cannot access bundle"},
+			{"wrongChecksum", "Checksum mismatch for file 'foo.yml'"},
+			{"unlistedFile", "File 'baz.yml' is not listed in the manifest"},
+			{"noManifest", "does not contain a manifest file"},
+		} {
+			t.Logf("Testing bundle '%v'", tc.bundle)
+			workDir := utils.GetTempDir()
+			defer os.RemoveAll(workDir)
+
+			targetDir := filepath.Join(workDir, "templates")
+			test.ExpectNoError(t, os.Mkdir(targetDir, 0755))
+			test.ExpectNoError(t, ioutil.WriteFile(filepath.Join(targetDir, "old.yml"), []byte("id: old"), 0644))
+
+			_, err := Update(filepath.Join(updateTestDir, tc.bundle), targetDir)
+			test.ExpectError(t, err, tc.errMsg)
+
+			// current templates must be untouched
+			test.ExpectFileExists(t, filepath.Join(targetDir, "old.yml"))
+			test.ExpectFalse(t, dirExists(GetPreviousDir(targetDir)))
+			expectNoLeftovers(t, workDir)
+		}
+
+		t.Run("archive with invalid path", func(t *testing.T) {
+			workDir := utils.GetTempDir()
+			defer os.RemoveAll(workDir)
+
+			bundle := filepath.Join(workDir, "bundle.zip")
+			writeZip(t, bundle, append(getBundleEntries(t, ""), archiveEntry{"../evil.yml", "id: evil"}))
+
+			_, err := Update(bundle, filepath.Join(workDir, "templates"))
+			test.ExpectError(t, err, "invalid path '../evil.yml'")
+			test.ExpectFalse(t, dirExists(filepath.Join(workDir, "templates")))
+		})
+
+		t.Run("unsupported format", func(t *testing.T) {
+			_, err := Update(filepath.Join(updateTestDir, "bundle", "foo.yml"), filepath.Join(updateTestDir, "doesNotExist"))
+			test.ExpectError(t, err, "Unsupported bundle format")
+		})
+
+		t.Run("http error", func(t *testing.T) {
+			server := httptest.NewServer(http.NotFoundHandler())
+			defer server.Close()
+
+			workDir := utils.GetTempDir()
+			defer os.RemoveAll(workDir)
+
+			_, err := Update(server.URL+"/bundle.zip", filepath.Join(workDir, "templates"))
+			test.ExpectError(t, err, "404")
+		})
+
+		t.Run("http timeout", func(t *testing.T) {
+			stalled := make(chan struct{})
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				<-stalled
+			}))
+			defer server.Close()
+			defer close(stalled)
+
+			formerTimeout := downloadClient.Timeout
+			defer func() { downloadClient.Timeout = formerTimeout }()
+			downloadClient.Timeout = 100 * time.Millisecond
+
+			workDir := utils.GetTempDir()
+			defer os.RemoveAll(workDir)
+
+			_, err := Update(server.URL+"/bundle.zip", filepath.Join(workDir, "templates"))
+			test.ExpectError(t, err, "Error downloading", "Timeout")
+		})
+
+		t.Run("previous templates are kept if they cannot be moved back", func(t *testing.T) {
+			workDir := utils.GetTempDir()
+			defer os.RemoveAll(workDir)
+
+			targetDir := filepath.Join(workDir, "templates")
+			prevDir := GetPreviousDir(targetDir)
+			for _, dir := range []string{targetDir, prevDir} {
+				test.ExpectNoError(t, os.MkdirAll(dir, 0755))
+			}
+			test.ExpectNoError(t, ioutil.WriteFile(filepath.Join(targetDir, "current.yml"), []byte("id: current"), 0644))
+			test.ExpectNoError(t, ioutil.WriteFile(filepath.Join(prevDir, "previous.yml"), []byte("id: previous"), 0644))
+
+			// only moving the previous templates away works
+			defer func() { rename = os.Rename }()
+			rename = func(from, to string) error {
+				if from == prevDir {
+					return os.Rename(from, to)
+				}
+				return fmt.Errorf("Rename not possible")
+			}
+
+			_, err := Update(filepath.Join(updateTestDir, "bundle"), targetDir)
+			test.ExpectError(t, err, "Error moving current templates", "The previous templates could not be moved back")
+
+			test.ExpectFileExists(t, filepath.Join(targetDir, "current.yml"))
+			matches, _ := filepath.Glob(filepath.Join(workDir, ".templates.update-*", "previous", "previous.yml"))
+			test.ExpectEqual(t, len(matches), 1)
+		})
+
+		t.Run("current templates are kept if they cannot be moved back", func(t *testing.T) {
+			workDir := utils.GetTempDir()
+			defer os.RemoveAll(workDir)
+
+			targetDir := filepath.Join(workDir, "templates")
+			prevDir := GetPreviousDir(targetDir)
+			for _, dir := range []string{targetDir, prevDir} {
+				test.ExpectNoError(t, os.MkdirAll(dir, 0755))
+			}
+			test.ExpectNoError(t, ioutil.WriteFile(filepath.Join(targetDir, "current.yml"), []byte("id: current"), 0644))
+			test.ExpectNoError(t, ioutil.WriteFile(filepath.Join(prevDir, "previous.yml"), []byte("id: previous"), 0644))
+
+			// nothing can be moved to the target dir
+			defer func() { rename = os.Rename }()
+			rename = func(from, to string) error {
+				if to != targetDir {
+					return os.Rename(from, to)
+				}
+				return fmt.Errorf("Rename not possible")
+			}
+
+			_, err := Update(filepath.Join(updateTestDir, "bundle"), targetDir)
+			test.ExpectError(t, err, "Error installing new templates", "The current templates could not be moved back", "The previous templates were kept")
+
+			test.ExpectFileExists(t, filepath.Join(prevDir, "current.yml"))
+			matches, _ := filepath.Glob(filepath.Join(workDir, ".templates.update-*", "previous", "previous.yml"))
+			test.ExpectEqual(t, len(matches), 1)
+		})
+	})
+
+	t.Run("valid", func(t *testing.T) {
+		t.Run("directory", func(t *testing.T) {
+			workDir := utils.GetTempDir()
+			defer os.RemoveAll(workDir)
+
+			targetDir := filepath.Join(workDir, "templates")
+			numFiles, err := Update(filepath.Join(updateTestDir, "bundle"), targetDir)
+			test.ExpectNoError(t, err)
+			test.ExpectEqual(t, numFiles, 2)
+			expectInstalledBundle(t, targetDir)
+			expectNoLeftovers(t, workDir)
+		})
+
+		for _, tc := range []struct {
+			filename string
+			prefix   string
+		}{
+			{"bundle.zip", ""},
+			{"bundle.zip", "pfscf-templates/"},
+			{"bundle.tar", ""},
+			{"bundle.tar.gz", "pfscf-templates/"},
+			{"bundle.tgz", ""},
+		} {
+			t.Logf("Testing archive '%v' with prefix '%v'", tc.filename, tc.prefix)
+			workDir := utils.GetTempDir()
+			defer os.RemoveAll(workDir)
+
+			bundle := filepath.Join(workDir, tc.filename)
+			switch tc.filename {
+			case "bundle.zip":
+				writeZip(t, bundle, getBundleEntries(t, tc.prefix))
+			default:
+				writeTar(t, bundle, tc.filename != "bundle.tar", getBundleEntries(t, tc.prefix))
+			}
+
+			targetDir := filepath.Join(workDir, "templates")
+			_, err := Update(bundle, targetDir)
+			test.ExpectNoError(t, err)
+			expectInstalledBundle(t, targetDir)
+		}
+
+		t.Run("http", func(t *testing.T) {
+			workDir := utils.GetTempDir()
+			defer os.RemoveAll(workDir)
+
+			serverDir := filepath.Join(workDir, "server")
+			test.ExpectNoError(t, os.Mkdir(serverDir, 0755))
+			writeZip(t, filepath.Join(serverDir, "bundle.zip"), getBundleEntries(t, ""))
+
+			server := httptest.NewServer(http.FileServer(http.Dir(serverDir)))
+			defer server.Close()
+
+			targetDir := filepath.Join(workDir, "templates")
+			_, err := Update(server.URL+"/bundle.zip", targetDir)
+			test.ExpectNoError(t, err)
+			expectInstalledBundle(t, targetDir)
+		})
+
+		t.Run("previous version is kept and can be restored", func(t *testing.T) {
+			workDir := utils.GetTempDir()
+			defer os.RemoveAll(workDir)
+
+			targetDir := filepath.Join(workDir, "templates")
+			test.ExpectNoError(t, os.Mkdir(targetDir, 0755))
+			test.ExpectNoError(t, ioutil.WriteFile(filepath.Join(targetDir, "old.yml"), []byte("id: old"), 0644))
+
+			_, err := Update(filepath.Join(updateTestDir, "bundle"), targetDir)
+			test.ExpectNoError(t, err)
+			expectInstalledBundle(t, targetDir)
+			test.ExpectFileExists(t, filepath.Join(GetPreviousDir(targetDir), "old.yml"))
+
+			// second update replaces the previous version
+			_, err = Update(filepath.Join(updateTestDir, "bundle"), targetDir)
+			test.ExpectNoError(t, err)
+			expectInstalledBundle(t, GetPreviousDir(targetDir))
+
+			// rollback swaps current and previous version
+			test.ExpectNoError(t, os.Remove(filepath.Join(targetDir, "foo.yml")))
+			test.ExpectNoError(t, Rollback(targetDir))
+			expectInstalledBundle(t, targetDir)
+			test.ExpectFalse(t, fileExists(filepath.Join(GetPreviousDir(targetDir), "foo.yml")))
+			expectNoLeftovers(t, workDir)
+		})
+	})
+}
+
+func TestRollback(t *testing.T) {
+	t.Run("errors", func(t *testing.T) {
+		t.Run("no previous version", func(t *testing.T) {
+			workDir := utils.GetTempDir()
+			defer os.RemoveAll(workDir)
+
+			err := Rollback(filepath.Join(workDir, "templates"))
+			test.ExpectError(t, err, "No previous templates found")
+		})
+
+		t.Run("current templates are kept if they cannot be moved back", func(t *testing.T) {
+			workDir := utils.GetTempDir()
+			defer os.RemoveAll(workDir)
+
+			targetDir := filepath.Join(workDir, "templates")
+			prevDir := GetPreviousDir(targetDir)
+			for _, dir := range []string{targetDir, prevDir} {
+				test.ExpectNoError(t, os.MkdirAll(dir, 0755))
+			}
+			test.ExpectNoError(t, ioutil.WriteFile(filepath.Join(targetDir, "current.yml"), []byte("id: current"), 0644))
+
+			// only moving the current templates away works
+			defer func() { rename = os.Rename }()
+			rename = func(from, to string) error {
+				if from == targetDir {
+					return os.Rename(from, to)
+				}
+				return fmt.Errorf("Rename not possible")
+			}
+
+			err := Rollback(targetDir)
+			test.ExpectError(t, err, "Error restoring previous templates", "The current templates could not be moved back")
+
+			matches, _ := filepath.Glob(filepath.Join(workDir, ".templates.rollback-*", "current", "current.yml"))
+			test.ExpectEqual(t, len(matches), 1)
+		})
+	})
+}
+
+func fileExists(filename string) bool {
+	_, err := os.Stat(filename)
+	return err == nil
+}
@@ -0,0 +1,23 @@
+package yaml
+
+// PathError is an error that belongs to a specific location inside a yaml
+// document. The location is described as path of map keys and list indices,
+// see GetLineForPath().
+type PathError struct {
+	Path []string
+	Err  error
+}
+
+// NewPathError wraps the provided error into a PathError with the provided path.
+// If the error is already a PathError, then the provided path is prepended
+// to the existing path.
+func NewPathError(err error, path ...string) *PathError {
+	if pe, ok := err.(*PathError); ok {
+		return &PathError{Path: append(append(make([]string, 0), path...), pe.Path...), Err: pe.Err}
+	}
+	return &PathError{Path: append(make([]string, 0), path...), Err: err}
+}
+
+func (e *PathError) Error() string {
+	return e.Err.Error()
+}
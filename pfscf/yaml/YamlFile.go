@@ -5,15 +5,33 @@ import (
 	"io/ioutil"
 	"path/filepath"
 	"regexp"
+	"strconv"
 	"strings"
 
 	"gopkg.in/yaml.v2"
+	yamlv3 "gopkg.in/yaml.v3"
 )
 
 const (
 	templateFilePattern = `^[^.].*\.yml$`
 )
 
+var (
+	regexErrorLine = regexp.MustCompile(`line (\d+)`)
+)
+
+// ParseError is returned by ReadYamlFile in case the content of a file could
+// not be parsed.
+type ParseError struct {
+	Filename string
+	Line     int // 0 if unknown
+	Err      error
+}
+
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("Parsing file '%v': %v", e.Filename, e.Err)
+}
+
 // GetYamlFilenamesFromDir takes a directory name as input and returns a list of names
 // of all yaml files within that dir and its subdirectories. All returned paths are
 // prefixed with the provided path argument.
@@ -41,7 +59,8 @@ func GetYamlFilenamesFromDir(dirName string) (yamlFilenames []string, err error)
 }
 
 // ReadYamlFile reads the yaml file from the provided location and stores the
-// data into the provided object.
+// data into the provided object. If the file content cannot be parsed, then
+// the returned error is of type *ParseError.
 func ReadYamlFile(filename string, ct interface{}) (err error) {
 	// TODO add assertion that interface is a ptr
 	fileData, err := ioutil.ReadFile(filename)
@@ -51,8 +70,73 @@ func ReadYamlFile(filename string, ct interface{}) (err error) {
 
 	err = yaml.Unmarshal(fileData, ct)
 	if err != nil {
-		return fmt.Errorf("Parsing file '%v': %v", filename, err)
+		return &ParseError{Filename: filename, Line: getLineFromError(err), Err: err}
 	}
 
 	return nil
 }
+
+// getLineFromError extracts the first line number mentioned in an error returned
+// by the yaml library. If no line number is included, 0 is returned.
+func getLineFromError(err error) (line int) {
+	match := regexErrorLine.FindStringSubmatch(err.Error())
+	if len(match) == 0 {
+		return 0
+	}
+
+	line, _ = strconv.Atoi(match[1]) // regex ensures that only digits are matched
+	return line
+}
+
+// GetLineForPath reads the provided yaml file and returns the line on which the node
+// denoted by the provided path is located. The path consists of map keys and, for
+// lists, of the index of the list entry, starting at 0. If the path cannot be followed
+// completely, then the line of the last node that could be found is returned.
+func GetLineForPath(filename string, path ...string) (line int, err error) {
+	fileData, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return 0, fmt.Errorf("Error reading file '%v': %v", filename, err)
+	}
+
+	var root yamlv3.Node
+	if err = yamlv3.Unmarshal(fileData, &root); err != nil {
+		return 0, &ParseError{Filename: filename, Line: getLineFromError(err), Err: err}
+	}
+
+	node := &root
+	if node.Kind == yamlv3.DocumentNode && len(node.Content) > 0 {
+		node = node.Content[0]
+	}
+	line = node.Line
+
+	for _, element := range path {
+		childLine, child := getChildNode(node, element)
+		if child == nil {
+			break
+		}
+		line, node = childLine, child
+	}
+
+	return line, nil
+}
+
+// getChildNode returns the child node with the provided key for mapping nodes or the
+// child node with the provided index for sequence nodes. For mapping nodes the line of
+// the key is returned, as the value of a nested map would start on the following line.
+// Returns a nil node if nothing was found.
+func getChildNode(node *yamlv3.Node, key string) (line int, child *yamlv3.Node) {
+	switch node.Kind {
+	case yamlv3.MappingNode:
+		// content contains keys and values alternating
+		for idx := 0; idx+1 < len(node.Content); idx += 2 {
+			if node.Content[idx].Value == key {
+				return node.Content[idx].Line, node.Content[idx+1]
+			}
+		}
+	case yamlv3.SequenceNode:
+		if idx, err := strconv.Atoi(key); err == nil && idx >= 0 && idx < len(node.Content) {
+			return node.Content[idx].Line, node.Content[idx]
+		}
+	}
+	return 0, nil
+}
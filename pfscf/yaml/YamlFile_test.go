@@ -47,3 +47,59 @@ func TestGetTemplateFilenamesFromDir(t *testing.T) {
 		})
 	})
 }
+
+func TestReadYamlFile(t *testing.T) {
+	t.Run("errors", func(t *testing.T) {
+		t.Run("parse error contains line", func(t *testing.T) {
+			filename := filepath.Join(yamlTestDir, "parseError.yml")
+			var data map[string]interface{}
+			err := ReadYamlFile(filename, &data)
+
+			test.ExpectError(t, err, "parseError.yml")
+			parseErr, ok := err.(*ParseError)
+			test.ExpectTrue(t, ok)
+			if ok {
+				test.ExpectEqual(t, parseErr.Filename, filename)
+				test.ExpectEqual(t, parseErr.Line, 3)
+			}
+		})
+	})
+}
+
+func TestGetLineForPath(t *testing.T) {
+	filename := filepath.Join(yamlTestDir, "lineNumbers.yml")
+
+	t.Run("errors", func(t *testing.T) {
+		t.Run("non-existing file", func(t *testing.T) {
+			_, err := GetLineForPath(filepath.Join(yamlTestDir, "doesNotExist.yml"))
+			test.ExpectError(t, err)
+		})
+
+		t.Run("malformed file", func(t *testing.T) {
+			_, err := GetLineForPath(filepath.Join(yamlTestDir, "parseError.yml"), "id")
+			test.ExpectError(t, err)
+		})
+	})
+
+	t.Run("valid", func(t *testing.T) {
+		for _, tc := range []struct {
+			path    []string
+			expLine int
+		}{
+			{[]string{}, 1},
+			{[]string{"id"}, 1},
+			{[]string{"presets", "preset0"}, 3},
+			{[]string{"presets", "preset0", "y"}, 4},
+			{[]string{"content"}, 5},
+			{[]string{"content", "0"}, 6},
+			{[]string{"content", "1", "x"}, 10},
+			{[]string{"content", "1", "doesNotExist"}, 9}, // deepest node found
+			{[]string{"content", "5"}, 5},
+		} {
+			t.Logf("Testing path %v", tc.path)
+			line, err := GetLineForPath(filename, tc.path...)
+			test.ExpectNoError(t, err)
+			test.ExpectEqual(t, line, tc.expLine)
+		}
+	})
+}